@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//      http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -14,15 +14,21 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils/provider_wrapper"
 
+	"github.com/hashicorp/terraform/terraform"
 	"github.com/spf13/pflag"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
@@ -38,12 +44,98 @@ type ImportOptions struct {
 	State       string
 	Bucket      string
 	Profile     string
-	Zone        string
-	Regions     []string
-	Projects    []string
-	Connect     bool
-	Compact     bool
-	Filter      []string
+	// AssumeRole is an IAM role ARN the AWS provider should assume before building its
+	// session, so a single run can sweep member accounts in an AWS Organization by
+	// re-invoking with a different role per account.
+	AssumeRole string
+	// AssumeRoleExternalID is the external ID to pass along with AssumeRole, for roles
+	// that require one.
+	AssumeRoleExternalID string
+	// Namespaces, if set, restricts the Kubernetes generators to the given
+	// comma-separated namespace(s), so the API server only returns matching objects
+	// instead of everything across the cluster. Cluster-scoped resources are
+	// unaffected; an empty value preserves the current all-namespaces behavior.
+	Namespaces string
+	Zone       string
+	Regions    []string
+	Projects   []string
+	Connect    bool
+	Compact    bool
+	Filter     []string
+	// FilterFile, if set, is the path to a YAML or JSON file of filters loaded with
+	// terraform_utils.LoadFilters and applied in addition to Filter.
+	FilterFile string
+	// ProviderVersion, if set, is a version constraint (e.g. "~> 2.70") the provider
+	// plugin used for refresh must satisfy; the generated config's required_providers
+	// block should be kept in sync with whatever version this actually resolves to.
+	ProviderVersion string
+	// RevealSensitiveValues opts back into emitting raw values for attributes a
+	// generator marked sensitive (passwords, private keys, tokens), instead of the
+	// default placeholder redaction.
+	RevealSensitiveValues bool
+	// DryRun runs InitResources and the filter/cleanup passes for each service, then
+	// prints what was found instead of refreshing, converting, or writing anything.
+	DryRun bool
+	// JSONLinesOutput writes resources.jsonl alongside the generated .tf files: one
+	// JSON object per line, for tooling that wants a machine-readable inventory of
+	// everything terraformer imported.
+	JSONLinesOutput bool
+	// ImportScript writes import.sh alongside the generated config: one `terraform
+	// import <address> <id>` per resource, for users who'd rather run the import
+	// themselves and review the diff than trust the generated state file.
+	ImportScript bool
+	// UseModules, when PathPattern includes {service}, additionally emits a root main.tf
+	// with a module block per service directory, so `terraform init` at the output root
+	// picks up every imported service as a child module. Cross-service references still
+	// flow through the existing data.terraform_remote_state mechanism (see
+	// ConnectServices) rather than module inputs/outputs: each service is imported and
+	// refreshed independently, and its own state is already the natural interface for
+	// other services to read from.
+	UseModules bool
+	// Account is a user-supplied identifier (e.g. an AWS account ID or alias) available
+	// to PathPattern as {account}, so a run against a specific account (see AssumeRole)
+	// can lay its output alongside other accounts' without colliding.
+	Account string
+	// S3Region is the region passed to the S3 client when State is "s3"; the bucket
+	// itself is Bucket, same as the "bucket" (GCS) mode.
+	S3Region string
+	// MergeState, with local state, merges newly imported resources into an existing
+	// terraform.tfstate at the output path instead of overwriting it, skipping (and
+	// logging) any resource whose address is already present.
+	MergeState bool
+	// CollapseForEach opts into terraform_utils.CollapseForEach: same-type resources
+	// differing only in a few attributes are rewritten as a single for_each resource,
+	// with the varying attributes moved into a locals.tf map.
+	CollapseForEach bool
+	// LabelFilter, for providers whose list API accepts a server-side filter
+	// expression (e.g. GCP's "labels.team=platform"), is passed through so only
+	// matching resources are refreshed, instead of listing everything and filtering
+	// post-hoc with Filter.
+	LabelFilter string
+	// RoleAssignmentScope restricts Azure's role_assignment service to
+	// assignments defined directly on this scope, dropping ones only
+	// inherited from an ancestor management group.
+	RoleAssignmentScope string
+	// KubernetesManifests, for the Kubernetes provider, additionally writes each
+	// resource's imported attributes as a YAML manifest under manifests/, organized
+	// by namespace, alongside the generated HCL.
+	KubernetesManifests bool
+	// NameTemplate, if set, overrides each resource's generated name with the result
+	// of rendering this template (see terraform_utils.ApplyNameTemplate) instead of
+	// the generator's own naming, e.g. "{type}-{id}".
+	NameTemplate string
+	// Since, if set, is an RFC3339 timestamp restricting import to resources created
+	// or modified at or after this time. Support is generator-specific: a generator
+	// consults terraform_utils.Service.Since itself, either turning it into a
+	// server-side list filter or filtering client-side with
+	// terraform_utils.FilterResourcesSince after InitResources.
+	Since string
+	// Verbose enables debug-level logging (e.g. filter evaluation failures that are
+	// normally suppressed), on top of the warnings terraformer always prints.
+	Verbose bool
+	// SummaryJSON prints the end-of-run per-service summary (see ServiceSummary) as a
+	// JSON array instead of a human-readable table, for CI to gate on RefreshFailed.
+	SummaryJSON bool
 	Plan        bool `json:"-"`
 }
 
@@ -72,6 +164,15 @@ func newImportCmd() *cobra.Command {
 }
 
 func Import(provider terraform_utils.ProviderGenerator, options ImportOptions, args []string) error {
+	if options.Verbose {
+		terraform_utils.SetLogLevel(terraform_utils.LogLevelDebug)
+	}
+	// Ctrl-C cancels the context RefreshResources/Resource.Refresh check between
+	// resources, so a long import can be stopped without waiting for every remaining
+	// resource to refresh.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	err := provider.Init(args)
 	if err != nil {
 		return err
@@ -82,6 +183,7 @@ func Import(provider terraform_utils.ProviderGenerator, options ImportOptions, a
 		Args:             args,
 		ImportedResource: map[string][]terraform_utils.Resource{},
 	}
+	var summaries []ServiceSummary
 
 	for _, service := range options.Resources {
 		log.Println(provider.GetName() + " importing... " + service)
@@ -90,23 +192,59 @@ func Import(provider terraform_utils.ProviderGenerator, options ImportOptions, a
 			return err
 		}
 		provider.GetService().ParseFilters(options.Filter)
+		if options.FilterFile != "" {
+			fileFilters, err := terraform_utils.LoadFilters(options.FilterFile)
+			if err != nil {
+				return err
+			}
+			provider.GetService().AddFilters(fileFilters)
+		}
+		provider.GetService().SetRevealSensitiveValues(options.RevealSensitiveValues)
+		if options.Since != "" {
+			since, err := time.Parse(time.RFC3339, options.Since)
+			if err != nil {
+				return fmt.Errorf("invalid --since %q: %s", options.Since, err)
+			}
+			provider.GetService().SetSince(since)
+		}
 		err = provider.GetService().InitResources()
 		provider.GetService().PopulateIgnoreKeys(provider.GetBasicConfig())
 		if err != nil {
 			return err
 		}
+		summary := ServiceSummary{Service: service, Initialized: len(provider.GetService().GetResources())}
 		provider.GetService().InitialCleanup()
+		summary.Filtered += summary.Initialized - len(provider.GetService().GetResources())
+		if options.NameTemplate != "" {
+			resources := provider.GetService().GetResources()
+			terraform_utils.ApplyNameTemplate(resources, options.NameTemplate)
+			terraform_utils.RenameDuplicateResources(resources)
+			provider.GetService().SetResources(resources)
+		}
+
+		if options.DryRun {
+			printDryRunResources(service, provider.GetService().GetResources())
+			continue
+		}
 
-		providerWrapper, err := provider_wrapper.NewProviderWrapper(provider.GetName(), provider.GetConfig())
+		var providerWrapper *provider_wrapper.ProviderWrapper
+		if options.ProviderVersion != "" {
+			providerWrapper, err = provider_wrapper.NewProviderWrapperWithVersion(provider.GetName(), provider.GetConfig(), options.ProviderVersion)
+		} else {
+			providerWrapper, err = provider_wrapper.NewProviderWrapper(provider.GetName(), provider.GetConfig())
+		}
 		if err != nil {
 			return err
 		}
 
-		refreshedResources, err := terraform_utils.RefreshResources(provider.GetService().GetResources(), providerWrapper)
+		preRefreshCount := len(provider.GetService().GetResources())
+		refreshedResources, err := terraform_utils.RefreshResources(ctx, provider.GetService().GetResources(), providerWrapper, terraform_utils.DefaultRefreshParallelism)
 		if err != nil {
 			return err
 		}
 		provider.GetService().SetResources(refreshedResources)
+		summary.RefreshedOK = len(refreshedResources)
+		summary.RefreshFailed = preRefreshCount - summary.RefreshedOK
 
 		for i := range provider.GetService().GetResources() {
 			err = provider.GetService().GetResources()[i].ConvertTFstate(providerWrapper)
@@ -115,9 +253,19 @@ func Import(provider terraform_utils.ProviderGenerator, options ImportOptions, a
 			}
 		}
 
+		if problems, err := terraform_utils.MissingRequiredAttributes(provider.GetService().GetResources(), providerWrapper); err != nil {
+			log.Println("could not validate required attributes:", err)
+		} else {
+			for _, problem := range problems {
+				log.Println("WARNING:", problem)
+			}
+		}
+
 		providerWrapper.Kill()
 
+		beforePostCleanup := len(provider.GetService().GetResources())
 		provider.GetService().PostRefreshCleanup()
+		summary.Filtered += beforePostCleanup - len(provider.GetService().GetResources())
 
 		// change structs with additional data for each resource
 		err = provider.GetService().PostConvertHook()
@@ -125,9 +273,16 @@ func Import(provider terraform_utils.ProviderGenerator, options ImportOptions, a
 			return err
 		}
 		plan.ImportedResource[service] = append(plan.ImportedResource[service], provider.GetService().GetResources()...)
+		summaries = append(summaries, summary)
+	}
+	if options.DryRun {
+		return nil
+	}
+	if err := printImportSummary(summaries, options.SummaryJSON); err != nil {
+		return err
 	}
 	if options.Plan {
-		path := Path(options.PathPattern, provider.GetName(), "terraformer", options.PathOutput)
+		path := Path(options.PathPattern, provider.GetName(), "terraformer", options.PathOutput, options.Account)
 		return ExportPlanFile(plan, path, "plan.json")
 	} else {
 		return ImportFromPlan(provider, plan)
@@ -160,18 +315,112 @@ func ImportFromPlan(provider terraform_utils.ProviderGenerator, plan *ImportPlan
 				return e
 			}
 		}
+		if options.UseModules {
+			if err := outputRootModule(provider, options, importedResource); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
+// outputRootModule writes a root main.tf wiring every service directory in as a child
+// module, using a path relative to the root so the result still works if PathOutput is
+// itself relative.
+func outputRootModule(provider terraform_utils.ProviderGenerator, options ImportOptions, importedResource map[string][]terraform_utils.Resource) error {
+	rootPath := Path(options.PathPattern, provider.GetName(), "", options.PathOutput, options.Account)
+	moduleSources := map[string]string{}
+	for serviceName := range importedResource {
+		servicePath := Path(options.PathPattern, provider.GetName(), serviceName, options.PathOutput, options.Account)
+		relPath, err := filepath.Rel(rootPath, servicePath)
+		if err != nil {
+			return err
+		}
+		moduleSources[serviceName] = "./" + relPath
+	}
+	return terraform_output.OutputRootModule(rootPath, moduleSources)
+}
+
+// mergeIntoExistingState reads the terraform.tfstate at statePath, if one exists, and
+// merges resources into it via terraform_utils.MergeTfState. It returns nil, nil when
+// there's nothing to merge into yet, so the caller falls back to writing a fresh state.
+func mergeIntoExistingState(statePath string, resources []terraform_utils.Resource) ([]byte, error) {
+	existingBytes, err := ioutil.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	existing, err := terraform.ReadState(bytes.NewReader(existingBytes))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse existing state at %s: %s", statePath, err)
+	}
+	conflicts, err := terraform_utils.MergeTfState(existing, resources)
+	if err != nil {
+		return nil, err
+	}
+	for _, address := range conflicts {
+		log.Println("WARNING: skipped merging " + address + ": already present in " + statePath)
+	}
+	var buf bytes.Buffer
+	if err := terraform.WriteState(existing, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func printService(provider terraform_utils.ProviderGenerator, serviceName string, options ImportOptions, resources []terraform_utils.Resource, importedResource map[string][]terraform_utils.Resource) error {
 	log.Println(provider.GetName() + " save " + serviceName)
 	// Print HCL files for Resources
-	path := Path(options.PathPattern, provider.GetName(), serviceName, options.PathOutput)
+	path := Path(options.PathPattern, provider.GetName(), serviceName, options.PathOutput, options.Account)
+	if options.CollapseForEach {
+		var localsData map[string]interface{}
+		resources, localsData = terraform_utils.CollapseForEach(resources)
+		if len(localsData) > 0 {
+			if options.State != "import-blocks" {
+				// terraform_utils.CollapseForEach's doc comment: the state written below
+				// only reflects the first member of each collapsed group, so anyone
+				// relying on the resulting terraform.tfstate for the rest needs to know
+				// it's incomplete rather than discover it later as a silent drift.
+				log.Println("WARNING: --collapse-for-each was used with state output; the written terraform.tfstate only reflects the first member of each collapsed group")
+			}
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+			if localsFile, err := terraform_utils.HclPrint(map[string]interface{}{"locals": localsData}, map[string]struct{}{}); err == nil {
+				terraform_output.PrintFile(path+"/locals.tf", localsFile)
+			}
+		}
+	}
 	err := terraform_output.OutputHclFiles(resources, provider, path, serviceName, options.Compact)
 	if err != nil {
 		return err
 	}
+	if options.JSONLinesOutput {
+		if err := terraform_output.OutputResourceInventory(resources, path+"/resources.jsonl"); err != nil {
+			return err
+		}
+	}
+	if options.ImportScript {
+		if err := terraform_output.OutputImportScript(resources, path); err != nil {
+			return err
+		}
+	}
+	if options.KubernetesManifests {
+		if err := terraform_output.OutputKubernetesManifests(resources, path); err != nil {
+			return err
+		}
+	}
+	if err := terraform_output.WriteDataFiles(resources, path); err != nil {
+		return err
+	}
+	if options.State == "import-blocks" {
+		// No terraform.tfstate is written in this mode, so the cross-service
+		// data.terraform_remote_state wiring below (which points at sibling
+		// terraform.tfstate files) doesn't apply; Terraform resolves everything itself
+		// once the imports are applied.
+		return terraform_output.OutputImportBlocks(resources, path)
+	}
 	tfStateFile, err := terraform_utils.PrintTfState(resources)
 	if err != nil {
 		return err
@@ -189,12 +438,31 @@ func printService(provider terraform_utils.ProviderGenerator, serviceName string
 		if bucketStateDataFile, err := terraform_utils.HclPrint(bucket.BucketGetTfData(path), map[string]struct{}{}); err == nil {
 			terraform_output.PrintFile(path+"/bucket.tf", bucketStateDataFile)
 		}
+	} else if options.State == "s3" {
+		log.Println(provider.GetName() + " upload tfstate to s3 bucket " + options.Bucket)
+		s3State := terraform_output.S3State{
+			Bucket: options.Bucket,
+			Region: options.S3Region,
+		}
+		if err := s3State.Upload(path, tfStateFile); err != nil {
+			return err
+		}
+		if backendDataFile, err := terraform_utils.HclPrint(s3State.BackendGetTfData(path), map[string]struct{}{}); err == nil {
+			terraform_output.PrintFile(path+"/backend.tf", backendDataFile)
+		}
 	} else {
 		if serviceName == "" {
 			log.Println(provider.GetName() + " save tfstate")
 		} else {
 			log.Println(provider.GetName() + " save tfstate for " + serviceName)
 		}
+		if options.MergeState {
+			if merged, err := mergeIntoExistingState(path+"/terraform.tfstate", resources); err != nil {
+				return err
+			} else if merged != nil {
+				tfStateFile = merged
+			}
+		}
 		if err := ioutil.WriteFile(path+"/terraform.tfstate", tfStateFile, os.ModePerm); err != nil {
 			return err
 		}
@@ -280,14 +548,25 @@ func printService(provider terraform_utils.ProviderGenerator, serviceName string
 	return nil
 }
 
-func Path(pathPattern, providerName, serviceName, output string) string {
+func Path(pathPattern, providerName, serviceName, output, account string) string {
 	return strings.NewReplacer(
 		"{provider}", providerName,
 		"{service}", serviceName,
 		"{output}", output,
+		"{account}", account,
 	).Replace(pathPattern)
 }
 
+// printDryRunResources prints what InitResources found for a service, without paying
+// for the refresh/convert/write stages, so a user can sanity-check filters and look
+// for missing resources before committing to a full import.
+func printDryRunResources(service string, resources []terraform_utils.Resource) {
+	fmt.Printf("%s: %d resource(s) found\n", service, len(resources))
+	for _, r := range resources {
+		fmt.Printf("  %s\timport_id=%s\tprovider=%s\n", r.InstanceInfo.Id, r.InstanceState.ID, r.Provider)
+	}
+}
+
 func listCmd(provider terraform_utils.ProviderGenerator) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -313,9 +592,24 @@ func baseProviderFlags(flag *pflag.FlagSet, options *ImportOptions, sampleRes, s
 	flag.BoolVarP(&options.Connect, "connect", "c", true, "")
 	flag.BoolVarP(&options.Compact, "compact", "C", false, "")
 	flag.StringSliceVarP(&options.Resources, "resources", "r", []string{}, sampleRes)
-	flag.StringVarP(&options.PathPattern, "path-pattern", "p", DefaultPathPattern, "{output}/{provider}/")
+	flag.StringVarP(&options.PathPattern, "path-pattern", "p", DefaultPathPattern, "{output}/{provider}/{service}/, also supports {account}")
 	flag.StringVarP(&options.PathOutput, "path-output", "o", DefaultPathOutput, "")
-	flag.StringVarP(&options.State, "state", "s", DefaultState, "local or bucket")
-	flag.StringVarP(&options.Bucket, "bucket", "b", "", "gs://terraform-state")
+	flag.StringVarP(&options.Account, "account", "", "", "value substituted for {account} in path-pattern")
+	flag.StringVarP(&options.State, "state", "s", DefaultState, "local, bucket (GCS), s3, or import-blocks (Terraform 1.5+ import blocks instead of a state file)")
+	flag.StringVarP(&options.Bucket, "bucket", "b", "", "gs://terraform-state, or the bucket name for --state=s3")
+	flag.StringVarP(&options.S3Region, "s3-region", "", "", "region for the S3 bucket, when --state=s3")
+	flag.BoolVarP(&options.MergeState, "merge-state", "", false, "with local state, merge into an existing terraform.tfstate instead of overwriting it")
+	flag.BoolVarP(&options.CollapseForEach, "collapse-for-each", "", false, "collapse same-type resources differing only in a few attributes into a single for_each resource")
 	flag.StringSliceVarP(&options.Filter, "filter", "f", []string{}, sampleFilters)
+	flag.StringVarP(&options.FilterFile, "filter-file", "", "", "path to a YAML or JSON file of filters, applied in addition to --filter")
+	flag.BoolVarP(&options.RevealSensitiveValues, "reveal-sensitive", "", false, "emit raw values for attributes generators mark sensitive, instead of a placeholder")
+	flag.BoolVarP(&options.DryRun, "dry-run", "", false, "list the resources InitResources found, without refreshing or writing any files")
+	flag.BoolVarP(&options.JSONLinesOutput, "json-lines", "", false, "also write resources.jsonl, one JSON object per imported resource")
+	flag.StringVarP(&options.ProviderVersion, "provider-version", "", "", "version constraint (e.g. \"~> 2.70\") the installed provider plugin used for refresh must satisfy")
+	flag.BoolVarP(&options.UseModules, "use-modules", "", false, "with a {service} path-pattern, also emit a root main.tf with a module block per service directory")
+	flag.BoolVarP(&options.ImportScript, "import-script", "", false, "also emit import.sh, one `terraform import` per resource")
+	flag.StringVarP(&options.NameTemplate, "name-template", "", "", "template for generated resource names, e.g. \"{type}-{id}\"")
+	flag.StringVarP(&options.Since, "since", "", "", "RFC3339 timestamp; only import resources created or modified at or after this time (generator-specific support)")
+	flag.BoolVarP(&options.Verbose, "verbose", "v", false, "enable debug-level logging, e.g. filter evaluation failures that are otherwise suppressed")
+	flag.BoolVarP(&options.SummaryJSON, "summary-json", "", false, "print the end-of-run per-service summary as a JSON array instead of a table")
 }
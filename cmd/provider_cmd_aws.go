@@ -30,10 +30,10 @@ func newCmdAwsImporter(options ImportOptions) *cobra.Command {
 		Long:  "Import current state to Terraform configuration from AWS",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			originalResources := options.Resources
-			originalRegions := options.Regions
+			originalRegions := dedupeRegions(options.Regions)
 			originalPathPattern := options.PathPattern
 
-			if len(options.Regions) > 0 {
+			if len(originalRegions) > 0 {
 				options.Resources = parseGlobalResources(originalResources)
 				options.Regions = []string{defaultRegion}
 				e := importGlobalResources(options)
@@ -64,6 +64,8 @@ func newCmdAwsImporter(options ImportOptions) *cobra.Command {
 
 	cmd.PersistentFlags().StringVarP(&options.Profile, "profile", "", "default", "prod")
 	cmd.PersistentFlags().StringSliceVarP(&options.Regions, "regions", "", []string{}, "eu-west-1,eu-west-2,us-east-1")
+	cmd.PersistentFlags().StringVarP(&options.AssumeRole, "assume-role", "", "", "arn:aws:iam::123456789012:role/OrganizationAccountAccessRole")
+	cmd.PersistentFlags().StringVarP(&options.AssumeRoleExternalID, "assume-role-external-id", "", "", "external ID required by --assume-role, if any")
 	return cmd
 }
 
@@ -104,7 +106,7 @@ func importRegionResources(options ImportOptions, originalPathPattern string, re
 	} else {
 		log.Println(provider.GetName() + " importing default region")
 	}
-	err := Import(provider, options, []string{region, options.Profile})
+	err := Import(provider, options, []string{region, options.Profile, options.AssumeRole, options.AssumeRoleExternalID})
 	if err != nil {
 		return err
 	}
@@ -115,6 +117,23 @@ func newAWSProvider() terraform_utils.ProviderGenerator {
 	return &aws_terraforming.AWSProvider{}
 }
 
+// dedupeRegions drops repeated region names, preserving first-seen order, so a --regions
+// value with an accidental duplicate (or the empty string, which would otherwise collide
+// with the global-resources pass's own defaultRegion) doesn't import the same region
+// twice.
+func dedupeRegions(regions []string) []string {
+	seen := map[string]bool{}
+	var deduped []string
+	for _, region := range regions {
+		if region == "" || seen[region] {
+			continue
+		}
+		seen[region] = true
+		deduped = append(deduped, region)
+	}
+	return deduped
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//      http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -27,7 +27,7 @@ func newCmdAzureImporter(options ImportOptions) *cobra.Command {
 		Long:  "Import current state to Terraform configuration from Azure",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := newAzureProvider()
-			err := Import(provider, options, []string{})
+			err := Import(provider, options, []string{options.RoleAssignmentScope})
 			if err != nil {
 				return err
 			}
@@ -37,6 +37,7 @@ func newCmdAzureImporter(options ImportOptions) *cobra.Command {
 
 	cmd.AddCommand(listCmd(newAzureProvider()))
 	baseProviderFlags(cmd.PersistentFlags(), &options, "resource_group", "resource_group=name1:name2:name3")
+	cmd.PersistentFlags().StringVarP(&options.RoleAssignmentScope, "role-assignment-scope", "", "", "/subscriptions/00000000-0000-0000-0000-000000000000")
 	return cmd
 }
 
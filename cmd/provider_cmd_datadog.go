@@ -20,14 +20,14 @@ import (
 )
 
 func newCmdDatadogImporter(options ImportOptions) *cobra.Command {
-	var apiKey, appKey string
+	var apiKey, appKey, tags, site string
 	cmd := &cobra.Command{
 		Use:   "datadog",
 		Short: "Import current state to Terraform configuration from Datadog",
 		Long:  "Import current state to Terraform configuration from Datadog",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := newDataDogProvider()
-			err := Import(provider, options, []string{apiKey, appKey})
+			err := Import(provider, options, []string{apiKey, appKey, tags, site})
 			if err != nil {
 				return err
 			}
@@ -38,6 +38,8 @@ func newCmdDatadogImporter(options ImportOptions) *cobra.Command {
 	baseProviderFlags(cmd.PersistentFlags(), &options, "monitors,users", "datadog_monitor=id1:id2:id4")
 	cmd.PersistentFlags().StringVarP(&apiKey, "api-key", "", "", "YOUR_DATADOG_API_KEY or env param DATADOG_API_KEY")
 	cmd.PersistentFlags().StringVarP(&appKey, "app-key", "", "", "YOUR_DATADOG_APP_KEY or env param DATADOG_APP_KEY")
+	cmd.PersistentFlags().StringVarP(&tags, "tags", "", "", "comma-separated monitor tags to filter by at the API level, e.g. env:prod,team:core")
+	cmd.PersistentFlags().StringVarP(&site, "site", "", "", "Datadog site to import from (datadoghq.com, datadoghq.eu, us3.datadoghq.com, us5.datadoghq.com, ddog-gov.com); defaults to datadoghq.com")
 	return cmd
 }
 
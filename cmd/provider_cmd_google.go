@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//      http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -35,7 +35,7 @@ func newCmdGoogleImporter(options ImportOptions) *cobra.Command {
 					options.PathPattern = originalPathPattern
 					options.PathPattern = strings.Replace(options.PathPattern, "{provider}/{service}", "{provider}/"+project+"/{service}/"+region, -1)
 					log.Println(provider.GetName() + " importing project " + project + " region " + region)
-					err := Import(provider, options, []string{region, project})
+					err := Import(provider, options, []string{region, project, options.LabelFilter})
 					if err != nil {
 						return err
 					}
@@ -49,6 +49,7 @@ func newCmdGoogleImporter(options ImportOptions) *cobra.Command {
 	cmd.PersistentFlags().StringSliceVarP(&options.Regions, "regions", "z", []string{"global"}, "europe-west1,")
 	cmd.PersistentFlags().StringSliceVarP(&options.Projects, "projects", "", []string{}, "")
 	_ = cmd.MarkPersistentFlagRequired("projects")
+	cmd.PersistentFlags().StringVarP(&options.LabelFilter, "label-filter", "", "", "labels.team=platform")
 	return cmd
 }
 
@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//      http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -26,7 +26,7 @@ func newCmdKubernetesImporter(options ImportOptions) *cobra.Command {
 		Long:  "Import current state to Terraform configuration from Kubernetes",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := newKubernetesProvider()
-			err := Import(provider, options, []string{})
+			err := Import(provider, options, []string{options.Namespaces})
 			if err != nil {
 				return err
 			}
@@ -36,6 +36,8 @@ func newCmdKubernetesImporter(options ImportOptions) *cobra.Command {
 
 	cmd.AddCommand(listCmd(newKubernetesProvider()))
 	baseProviderFlags(cmd.PersistentFlags(), &options, "configmaps,deployments,services", "kubernetes_deployment=name1:name2:name3")
+	cmd.PersistentFlags().StringVarP(&options.Namespaces, "namespace", "", "", "namespace1,namespace2")
+	cmd.PersistentFlags().BoolVarP(&options.KubernetesManifests, "manifests", "", false, "also write each resource as a YAML manifest under manifests/, alongside the generated HCL")
 	return cmd
 }
 
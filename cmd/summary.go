@@ -0,0 +1,53 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+// ServiceSummary is the per-service resource count Import accumulates as it works
+// through InitResources/filtering/refresh, so a run reports how many resources it
+// found versus how many actually made it into the generated config.
+type ServiceSummary struct {
+	Service       string `json:"service"`
+	Initialized   int    `json:"initialized"`
+	Filtered      int    `json:"filtered"`
+	RefreshedOK   int    `json:"refreshed_ok"`
+	RefreshFailed int    `json:"refresh_failed"`
+}
+
+// printImportSummary prints a table of summaries to stdout, or, if asJSON is set, the
+// same data as a JSON array, so CI can gate on RefreshFailed being nonzero without
+// scraping the table.
+func printImportSummary(summaries []ServiceSummary, asJSON bool) error {
+	if asJSON {
+		encoded, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(encoded))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tINITIALIZED\tFILTERED\tREFRESHED OK\tREFRESH FAILED")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", s.Service, s.Initialized, s.Filtered, s.RefreshedOK, s.RefreshFailed)
+	}
+	return w.Flush()
+}
@@ -87,28 +87,18 @@ func (p AliCloudProvider) GetProviderData(arg ...string) map[string]interface{}
 	}
 
 	if config.RamRoleArn != "" {
-		return map[string]interface{}{
-			"provider": map[string]interface{}{
-				"alicloud": map[string]interface{}{
-					"version": provider_wrapper.GetProviderVersion(p.GetName()),
-					"region":  region,
-					"profile": profile,
-					"assume_role": map[string]interface{}{
-						"role_arn": config.RamRoleArn,
-					},
-				},
+		return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+			"region":  region,
+			"profile": profile,
+			"assume_role": map[string]interface{}{
+				"role_arn": config.RamRoleArn,
 			},
-		}
-	}
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"alicloud": map[string]interface{}{
-				"region":  region,
-				"profile": profile,
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
+		})
 	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"region":  region,
+		"profile": profile,
+	})
 }
 
 // Init Loads up command line arguments in the provider
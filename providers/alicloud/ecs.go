@@ -46,12 +46,14 @@ func (g *EcsGenerator) InitResources() error {
 		return err
 	}
 	var filters []ecs.DescribeInstancesTag
-	for _, filter := range g.Filter {
-		if strings.HasPrefix(filter.FieldPath, "tags.") {
-			filters = append(filters, ecs.DescribeInstancesTag{
-				Key:   strings.TrimPrefix(filter.FieldPath, "tags."),
-				Value: filter.AcceptableValues[0],
-			})
+	for _, group := range g.Filter {
+		for _, filter := range group.Filters {
+			if strings.HasPrefix(filter.FieldPath, "tags.") {
+				filters = append(filters, ecs.DescribeInstancesTag{
+					Key:   strings.TrimPrefix(filter.FieldPath, "tags."),
+					Value: filter.AcceptableValues[0],
+				})
+			}
 		}
 	}
 
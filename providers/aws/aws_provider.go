@@ -26,8 +26,11 @@ import (
 
 type AWSProvider struct {
 	terraform_utils.Provider
-	region  string
-	profile string
+	region     string
+	profile    string
+	assumeRole string
+	// assumeRoleExternalID is passed along with assumeRole for roles that require one.
+	assumeRoleExternalID string
 }
 
 // global resources should be bound to a default region. AWS doesn't specify in which region default services are
@@ -81,6 +84,9 @@ func (p AWSProvider) GetResourceConnections() map[string]map[string][]string {
 			"subnet": []string{"subnets", "id"},
 		},
 		"igw": {"vpc": []string{"vpc_id", "id"}},
+		"lambda": {
+			"iam": []string{"role", "arn"},
+		},
 		"msk": {
 			"subnet": []string{"broker_node_group_info.client_subnets", "id"},
 			"sg":     []string{"broker_node_group_info.security_groups", "id"},
@@ -110,25 +116,19 @@ func (p AWSProvider) GetResourceConnections() map[string]map[string][]string {
 			"sqs": []string{"endpoint", "arn"},
 		},
 		"subnet":         {"vpc": []string{"vpc_id", "id"}},
+		"vpc_endpoint":   {"vpc": []string{"vpc_id", "id"}},
 		"vpn_gateway":    {"vpc": []string{"vpc_id", "id"}},
 		"vpn_connection": {"vpn_gateway": []string{"vpn_gateway_id", "id"}},
 	}
 }
 
 func (p AWSProvider) GetProviderData(arg ...string) map[string]interface{} {
-	awsConfig := map[string]interface{}{
-		"version": provider_wrapper.GetProviderVersion(p.GetName()),
-	}
-
+	awsConfig := map[string]interface{}{}
 	if p.region != "" {
 		awsConfig["region"] = p.region
 	}
 
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"aws": awsConfig,
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), awsConfig)
 }
 
 func (p *AWSProvider) GetConfig() cty.Value {
@@ -146,6 +146,12 @@ func (p *AWSProvider) GetBasicConfig() cty.Value {
 func (p *AWSProvider) Init(args []string) error {
 	p.region = args[0]
 	p.profile = args[1]
+	if len(args) > 2 {
+		p.assumeRole = args[2]
+	}
+	if len(args) > 3 {
+		p.assumeRoleExternalID = args[3]
+	}
 
 	// Terraformer accepts region and profile configuration, so we must detect what env variables to adjust to make Go SDK rely on them. AWS_SDK_LOAD_CONFIG here must be checked to determine correct variable to set.
 	enableSharedConfig, _ := strconv.ParseBool(os.Getenv("AWS_SDK_LOAD_CONFIG"))
@@ -187,8 +193,10 @@ func (p *AWSProvider) InitService(serviceName string) error {
 	p.Service.SetName(serviceName)
 	p.Service.SetProviderName(p.GetName())
 	p.Service.SetArgs(map[string]interface{}{
-		"region":                 p.region,
-		"skip_region_validation": true,
+		"region":                  p.region,
+		"skip_region_validation":  true,
+		"assume_role":             p.assumeRole,
+		"assume_role_external_id": p.assumeRoleExternalID,
 	})
 	return nil
 }
@@ -218,6 +226,7 @@ func (p *AWSProvider) GetSupportedService() map[string]terraform_utils.ServiceGe
 		"iam":            &IamGenerator{},
 		"igw":            &IgwGenerator{},
 		"kinesis":        &KinesisGenerator{},
+		"lambda":         &LambdaGenerator{},
 		"msk":            &MskGenerator{},
 		"nacl":           &NaclGenerator{},
 		"nat":            &NatGatewayGenerator{},
@@ -231,8 +240,10 @@ func (p *AWSProvider) GetSupportedService() map[string]terraform_utils.ServiceGe
 		"sns":            &SnsGenerator{},
 		"subnet":         &SubnetGenerator{},
 		"vpc":            &VpcGenerator{},
+		"vpc_endpoint":   &VpcEndpointGenerator{},
 		"vpc_peering":    &VpcPeeringConnectionGenerator{},
 		"vpn_connection": &VpnConnectionGenerator{},
 		"vpn_gateway":    &VpnGatewayGenerator{},
+		"wafv2":          &WafV2Generator{},
 	}
 }
@@ -32,6 +32,14 @@ func (s *AWSService) generateSession() *session.Session {
 		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
 	}))
 
+	if roleARN, _ := s.Args["assume_role"].(string); roleARN != "" {
+		sess.Config.Credentials = stscreds.NewCredentials(sess, roleARN, func(p *stscreds.AssumeRoleProvider) {
+			if externalID, _ := s.Args["assume_role_external_id"].(string); externalID != "" {
+				p.ExternalID = &externalID
+			}
+		})
+	}
+
 	// terraform cannot ask for MFA token, so we need to pass STS session token, which might contain credentials with MFA requirement
 	accessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
 	if accessKey == "" {
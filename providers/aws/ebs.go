@@ -39,12 +39,14 @@ func (g *EbsGenerator) InitResources() error {
 	sess := g.generateSession()
 	svc := ec2.New(sess)
 	var filters []*ec2.Filter
-	for _, filter := range g.Filter {
-		if strings.HasPrefix(filter.FieldPath, "tags.") && filter.IsApplicable("aws_ebs_volume") {
-			filters = append(filters, &ec2.Filter{
-				Name:   aws.String("tag:" + strings.TrimPrefix(filter.FieldPath, "tags.")),
-				Values: aws.StringSlice(filter.AcceptableValues),
-			})
+	for _, group := range g.Filter {
+		for _, filter := range group.Filters {
+			if strings.HasPrefix(filter.FieldPath, "tags.") && filter.IsApplicable("aws_ebs_volume") {
+				filters = append(filters, &ec2.Filter{
+					Name:   aws.String("tag:" + strings.TrimPrefix(filter.FieldPath, "tags.")),
+					Values: aws.StringSlice(filter.AcceptableValues),
+				})
+			}
 		}
 	}
 	input := ec2.DescribeVolumesInput{
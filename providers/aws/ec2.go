@@ -16,6 +16,7 @@ package aws
 
 import (
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
 
@@ -33,12 +34,14 @@ func (g *Ec2Generator) InitResources() error {
 	sess := g.generateSession()
 	svc := ec2.New(sess)
 	var filters []*ec2.Filter
-	for _, filter := range g.Filter {
-		if strings.HasPrefix(filter.FieldPath, "tags.") && filter.IsApplicable("aws_instance") {
-			filters = append(filters, &ec2.Filter{
-				Name:   aws.String("tag:" + strings.TrimPrefix(filter.FieldPath, "tags.")),
-				Values: aws.StringSlice(filter.AcceptableValues),
-			})
+	for _, group := range g.Filter {
+		for _, filter := range group.Filters {
+			if strings.HasPrefix(filter.FieldPath, "tags.") && filter.IsApplicable("aws_instance") {
+				filters = append(filters, &ec2.Filter{
+					Name:   aws.String("tag:" + strings.TrimPrefix(filter.FieldPath, "tags.")),
+					Values: aws.StringSlice(filter.AcceptableValues),
+				})
+			}
 		}
 	}
 	input := ec2.DescribeInstancesInput{
@@ -61,6 +64,10 @@ func (g *Ec2Generator) InitResources() error {
 				if err == nil && attr.UserData != nil && attr.UserData.Value != nil {
 					userDataBase64 = aws.StringValue(attr.UserData.Value)
 				}
+				launchTime := ""
+				if instance.LaunchTime != nil {
+					launchTime = instance.LaunchTime.Format(time.RFC3339)
+				}
 				r := terraform_utils.NewResource(
 					aws.StringValue(instance.InstanceId),
 					aws.StringValue(instance.InstanceId)+"_"+name,
@@ -69,6 +76,7 @@ func (g *Ec2Generator) InitResources() error {
 					map[string]string{
 						"user_data_base64":  userDataBase64,
 						"source_dest_check": "true",
+						"launch_time":       launchTime,
 					},
 					ec2AllowEmptyValues,
 					map[string]interface{}{},
@@ -83,6 +91,10 @@ func (g *Ec2Generator) InitResources() error {
 	if err != nil {
 		return err
 	}
+	// ec2.Filter only supports exact-match/wildcard values, so a "since" restriction
+	// can't be expressed server-side; filter the launch_time attribute set above
+	// instead.
+	g.Resources = terraform_utils.FilterResourcesSince(g.Resources, g.Since, "launch_time")
 	return nil
 
 }
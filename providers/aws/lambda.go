@@ -0,0 +1,99 @@
+// Copyright 2019 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"log"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+)
+
+var lambdaAllowEmptyValues = []string{"tags.", "environment.", "vpc_config."}
+
+type LambdaGenerator struct {
+	AWSService
+}
+
+// InitResources lists every function and, for each one, resolves the S3 location its
+// code currently lives at, so the generated resource points at real code instead of an
+// empty deployment package apply would try to replace on the next plan.
+func (g *LambdaGenerator) InitResources() error {
+	sess := g.generateSession()
+	svc := lambda.New(sess)
+	g.Resources = []terraform_utils.Resource{}
+
+	err := svc.ListFunctionsPages(&lambda.ListFunctionsInput{}, func(page *lambda.ListFunctionsOutput, lastPage bool) bool {
+		for _, function := range page.Functions {
+			name := aws.StringValue(function.FunctionName)
+			resource := terraform_utils.NewSimpleResource(
+				name,
+				name,
+				"aws_lambda_function",
+				"aws",
+				lambdaAllowEmptyValues,
+			)
+			g.setCodeLocation(svc, name, &resource)
+			resource.AddIgnoreChanges("source_code_hash")
+			g.Resources = append(g.Resources, resource)
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// setCodeLocation resolves function's current code package to s3_bucket/s3_key, so the
+// generated aws_lambda_function doesn't fall back to an empty filename that apply would
+// try to enforce. Failures are logged and skipped rather than aborting the import,
+// since the function itself still imports fine without its code location.
+func (g *LambdaGenerator) setCodeLocation(svc *lambda.Lambda, name string, resource *terraform_utils.Resource) {
+	out, err := svc.GetFunction(&lambda.GetFunctionInput{FunctionName: aws.String(name)})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	location := aws.StringValue(out.Code.Location)
+	if location == "" {
+		return
+	}
+	bucket, key, ok := parseLambdaCodeLocation(location)
+	if !ok {
+		return
+	}
+	resource.AdditionalFields["s3_bucket"] = bucket
+	resource.AdditionalFields["s3_key"] = key
+}
+
+// parseLambdaCodeLocation extracts the S3 bucket/key GetFunction's presigned Location
+// URL points at, e.g. "https://awslambda-us-east-1-tasks.s3.us-east-1.amazonaws.com/snapshots/123/my-function-abc?...".
+func parseLambdaCodeLocation(location string) (bucket, key string, ok bool) {
+	withoutScheme := strings.TrimPrefix(strings.TrimPrefix(location, "https://"), "http://")
+	hostAndPath := strings.SplitN(withoutScheme, "/", 2)
+	if len(hostAndPath) != 2 {
+		return "", "", false
+	}
+	bucket = strings.SplitN(hostAndPath[0], ".", 2)[0]
+	key = strings.SplitN(hostAndPath[1], "?", 2)[0]
+	if bucket == "" || key == "" {
+		return "", "", false
+	}
+	return bucket, key, true
+}
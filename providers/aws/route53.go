@@ -133,6 +133,9 @@ func (g *Route53Generator) PostConvertHook() error {
 			if _, ttlExist := resourceRecord.Item["ttl"]; ttlExist {
 				delete(g.Resources[i].Item, "ttl")
 			}
+			if _, recordsExist := resourceRecord.Item["records"]; recordsExist {
+				delete(g.Resources[i].Item, "records")
+			}
 		}
 	}
 	return nil
@@ -115,18 +115,19 @@ POLICY`, policy)
 }
 
 func (g *S3Generator) ParseFilters(rawFilters []string) {
-	g.Filter = []terraform_utils.ResourceFilter{}
+	g.Filter = []terraform_utils.ResourceFilterGroup{}
 	for _, rawFilter := range rawFilters {
-		filters := g.ParseFilter(rawFilter)
-		for _, resourceFilter := range filters {
-			g.Filter = append(g.Filter, resourceFilter)
-			if resourceFilter.ResourceName == "aws_s3_bucket" {
-				g.Filter = append(g.Filter, terraform_utils.ResourceFilter{
-					ResourceName:     "aws_s3_bucket_policy",
-					FieldPath:        resourceFilter.FieldPath,
-					AcceptableValues: resourceFilter.AcceptableValues,
-				})
+		for _, group := range g.ParseFilter(rawFilter) {
+			for _, resourceFilter := range group.Filters {
+				if resourceFilter.ResourceName == "aws_s3_bucket" {
+					group.Filters = append(group.Filters, terraform_utils.ResourceFilter{
+						ResourceName:     "aws_s3_bucket_policy",
+						FieldPath:        resourceFilter.FieldPath,
+						AcceptableValues: resourceFilter.AcceptableValues,
+					})
+				}
 			}
+			g.Filter = append(g.Filter, group)
 		}
 	}
 }
@@ -78,9 +78,43 @@ func (g *SecurityGenerator) InitResources() error {
 	return nil
 }
 
+// findSecurityGroupReference looks up id among the imported aws_security_group
+// resources, returning the interpolation string to reference it, or ok=false if id
+// wasn't imported (in which case the literal ID should be left as-is).
+func (g *SecurityGenerator) findSecurityGroupReference(id string) (string, bool) {
+	for _, i := range g.Resources {
+		if i.InstanceState.ID == id {
+			return "${" + i.InstanceInfo.Type + "." + i.ResourceName + ".id}", true
+		}
+	}
+	return "", false
+}
+
+// securityGroupIDRuleKeys are the standalone aws_security_group_rule attributes that
+// hold a literal security group ID rather than a CIDR block; a self-referencing rule
+// (source_security_group_id equal to the rule's own security_group_id) resolves to the
+// same resource, since both keys are looked up against the same set of imported groups.
+var securityGroupIDRuleKeys = []string{"security_group_id", "source_security_group_id"}
+
 // PostGenerateHook - replace sg-xxxxx string to terraform ID in all security group
+// rules, whether the rule is embedded in an aws_security_group's ingress/egress block
+// (the "security_groups" list) or is a standalone aws_security_group_rule resource
+// (the "security_group_id"/"source_security_group_id" attributes).
 func (g *SecurityGenerator) PostConvertHook() error {
 	for j, resource := range g.Resources {
+		for _, key := range securityGroupIDRuleKeys {
+			id, exist := resource.Item[key]
+			if !exist {
+				continue
+			}
+			idStr, ok := id.(string)
+			if !ok {
+				continue
+			}
+			if ref, found := g.findSecurityGroupReference(idStr); found {
+				g.Resources[j].Item[key] = ref
+			}
+		}
 		for _, typeOfRule := range []string{"ingress", "egress"} {
 			if _, exist := resource.Item[typeOfRule]; !exist {
 				continue
@@ -94,15 +128,9 @@ func (g *SecurityGenerator) PostConvertHook() error {
 					securityGroups := ingress.([]interface{})
 					renamedSecurityGroups := []string{}
 					for _, securityGroup := range securityGroups {
-						found := false
-						for _, i := range g.Resources {
-							if i.InstanceState.ID == securityGroup {
-								renamedSecurityGroups = append(renamedSecurityGroups, "${"+i.InstanceInfo.Type+"."+i.ResourceName+".id}")
-								found = true
-								break
-							}
-						}
-						if !found {
+						if ref, found := g.findSecurityGroupReference(securityGroup.(string)); found {
+							renamedSecurityGroups = append(renamedSecurityGroups, ref)
+						} else {
 							renamedSecurityGroups = append(renamedSecurityGroups, securityGroup.(string))
 						}
 					}
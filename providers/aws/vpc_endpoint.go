@@ -0,0 +1,75 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"log"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+var vpcEndpointAllowEmptyValues = []string{"tags.", "route_table_ids.", "policy"}
+
+type VpcEndpointGenerator struct {
+	AWSService
+}
+
+func (g VpcEndpointGenerator) createVpcEndpointResources(svc *ec2.EC2) []terraform_utils.Resource {
+	resources := []terraform_utils.Resource{}
+	err := svc.DescribeVpcEndpointsPages(
+		&ec2.DescribeVpcEndpointsInput{},
+		func(endpoints *ec2.DescribeVpcEndpointsOutput, lastPage bool) bool {
+			for _, endpoint := range endpoints.VpcEndpoints {
+				resources = append(resources, terraform_utils.NewSimpleResource(
+					aws.StringValue(endpoint.VpcEndpointId),
+					aws.StringValue(endpoint.VpcEndpointId),
+					"aws_vpc_endpoint",
+					"aws",
+					vpcEndpointAllowEmptyValues,
+				))
+			}
+			return true
+		},
+	)
+
+	if err != nil {
+		log.Println(err)
+		return resources
+	}
+
+	return resources
+}
+
+// Generate TerraformResources from AWS API,
+// create terraform resource for each VPC Endpoint
+func (g *VpcEndpointGenerator) InitResources() error {
+	sess := g.generateSession()
+	svc := ec2.New(sess)
+
+	g.Resources = g.createVpcEndpointResources(svc)
+	return nil
+}
+
+// PostConvertHook drops the endpoint's auto-created dns_entry computed block, which
+// terraform_provider_aws doesn't accept as user input.
+func (g *VpcEndpointGenerator) PostConvertHook() error {
+	for _, r := range g.Resources {
+		delete(r.Item, "dns_entry")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/wafv2"
+)
+
+var wafv2AllowEmptyValues = []string{"tags.", "rule.", "visibility_config."}
+
+// wafv2Scopes are the two scopes a web ACL can live in: REGIONAL ACLs attach to
+// resources like ALBs, CLOUDFRONT ACLs attach to CloudFront distributions and must be
+// queried from us-east-1 regardless of the configured region.
+var wafv2Scopes = []string{wafv2.ScopeRegional, wafv2.ScopeCloudfront}
+
+type WafV2Generator struct {
+	AWSService
+}
+
+func (g WafV2Generator) createResources(svc *wafv2.WAFV2, scope string) ([]terraform_utils.Resource, error) {
+	var resources []terraform_utils.Resource
+	summaries, err := svc.ListWebACLs(&wafv2.ListWebACLsInput{Scope: aws.String(scope)})
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range summaries.WebACLs {
+		acl, err := svc.GetWebACL(&wafv2.GetWebACLInput{
+			Id:    summary.Id,
+			Name:  summary.Name,
+			Scope: aws.String(scope),
+		})
+		if err != nil {
+			return nil, err
+		}
+		// The ImportID for aws_wafv2_web_acl is the composite Id/Name/Scope.
+		importID := aws.StringValue(acl.WebACL.Id) + "/" + aws.StringValue(acl.WebACL.Name) + "/" + scope
+		resources = append(resources, terraform_utils.NewSimpleResource(
+			importID,
+			aws.StringValue(acl.WebACL.Name),
+			"aws_wafv2_web_acl",
+			"aws",
+			wafv2AllowEmptyValues))
+	}
+	return resources, nil
+}
+
+// Generate TerraformResources from AWS API, iterating both the REGIONAL and CLOUDFRONT
+// scopes since a web ACL only exists in one of them. CLOUDFRONT is queried with a
+// separate, us-east-1-pinned client regardless of the configured region, per
+// wafv2Scopes' doc comment.
+func (g *WafV2Generator) InitResources() error {
+	sess := g.generateSession()
+	svc := wafv2.New(sess)
+	cloudfrontSvc := wafv2.New(sess.Copy(&aws.Config{Region: aws.String("us-east-1")}))
+	for _, scope := range wafv2Scopes {
+		scopeSvc := svc
+		if scope == wafv2.ScopeCloudfront {
+			scopeSvc = cloudfrontSvc
+		}
+		resources, err := g.createResources(scopeSvc, scope)
+		if err != nil {
+			return err
+		}
+		g.Resources = append(g.Resources, resources...)
+	}
+	return nil
+}
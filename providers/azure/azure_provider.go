@@ -24,7 +24,8 @@ import (
 
 type AzureProvider struct {
 	terraform_utils.Provider
-	subscription string
+	subscription        string
+	roleAssignmentScope string
 }
 
 func (p *AzureProvider) Init(args []string) error {
@@ -59,6 +60,10 @@ func (p *AzureProvider) Init(args []string) error {
 		return errors.New("set AZURE_TENANT_ID env var")
 	}
 
+	if len(args) > 0 {
+		p.roleAssignmentScope = args[0]
+	}
+
 	return nil
 }
 
@@ -67,13 +72,7 @@ func (p *AzureProvider) GetName() string {
 }
 
 func (p *AzureProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"azurerm": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData("azurerm", provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
 
 func (AzureProvider) GetResourceConnections() map[string]map[string][]string {
@@ -85,7 +84,9 @@ func (p *AzureProvider) GetSupportedService() map[string]terraform_utils.Service
 		"disk":                   &DiskGenerator{},
 		"network_interface":      &NetworkInterfaceGenerator{},
 		"network_security_group": &NetworkSecurityGroupGenerator{},
+		"private_endpoint":       &PrivateEndpointGenerator{},
 		"resource_group":         &ResourceGroupGenerator{},
+		"role_assignment":        &RoleAssignmentGenerator{},
 		"storage_account":        &StorageAccountGenerator{},
 		"virtual_machine":        &VirtualMachineGenerator{},
 		"virtual_network":        &VirtualNetworkGenerator{},
@@ -102,6 +103,7 @@ func (p *AzureProvider) InitService(serviceName string) error {
 	p.Service.SetProviderName(p.GetName())
 	p.Service.SetArgs(map[string]interface{}{
 		"subscription": p.subscription,
+		"scope":        p.roleAssignmentScope,
 	})
 	return nil
 }
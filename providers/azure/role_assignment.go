@@ -0,0 +1,91 @@
+// Copyright 2019 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azure
+
+import (
+	"context"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/services/authorization/mgmt/2015-07-01/authorization"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+var RoleAssignmentAllowEmptyValues = []string{"condition", "description"}
+
+type RoleAssignmentGenerator struct {
+	AzureService
+}
+
+func (g RoleAssignmentGenerator) createResources(assignmentListResultIterator authorization.RoleAssignmentListResultIterator, scope string) []terraform_utils.Resource {
+	var resources []terraform_utils.Resource
+	for assignmentListResultIterator.NotDone() {
+		assignment := assignmentListResultIterator.Value()
+		properties := assignment.Properties
+		if scope != "" && properties.Scope != nil && *properties.Scope != scope {
+			// assignment is inherited from a management group (or another
+			// ancestor scope) rather than defined directly on scope, skip it
+			if err := assignmentListResultIterator.Next(); err != nil {
+				log.Println(err)
+				break
+			}
+			continue
+		}
+		resources = append(resources, terraform_utils.NewResource(
+			*assignment.ID,
+			*assignment.Name,
+			"azurerm_role_assignment",
+			"azurerm",
+			map[string]string{
+				"scope":              *properties.Scope,
+				"role_definition_id": *properties.RoleDefinitionID,
+				"principal_id":       *properties.PrincipalID,
+			},
+			RoleAssignmentAllowEmptyValues,
+			map[string]interface{}{},
+		))
+		if err := assignmentListResultIterator.Next(); err != nil {
+			log.Println(err)
+			break
+		}
+	}
+	return resources
+}
+
+func (g *RoleAssignmentGenerator) InitResources() error {
+	ctx := context.Background()
+	subscriptionID := g.Args["subscription"].(string)
+	assignmentsClient := authorization.NewRoleAssignmentsClient(subscriptionID)
+	authorizer, err := auth.NewAuthorizerFromEnvironment()
+	if err != nil {
+		return err
+	}
+	assignmentsClient.Authorizer = authorizer
+
+	// RoleAssignmentsClient has no subscription-wide list call; the subscription
+	// itself is just another scope, so an empty "scope" arg lists role assignments
+	// across the whole subscription via ListForScope instead.
+	scope, _ := g.Args["scope"].(string)
+	listScope := scope
+	if listScope == "" {
+		listScope = "/subscriptions/" + subscriptionID
+	}
+	output, err := assignmentsClient.ListForScopeComplete(ctx, listScope, "")
+	if err != nil {
+		return err
+	}
+	g.Resources = g.createResources(output, scope)
+	return nil
+}
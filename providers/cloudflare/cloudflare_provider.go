@@ -34,13 +34,7 @@ func (p *CloudflareProvider) GetName() string {
 }
 
 func (p *CloudflareProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"cloudflare": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
 
 func (CloudflareProvider) GetResourceConnections() map[string]map[string][]string {
@@ -52,6 +46,7 @@ func (p *CloudflareProvider) GetSupportedService() map[string]terraform_utils.Se
 		"access":   &AccessGenerator{},
 		"dns":      &DNSGenerator{},
 		"firewall": &FirewallGenerator{},
+		"ruleset":  &RulesetGenerator{},
 	}
 }
 
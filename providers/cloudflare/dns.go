@@ -68,7 +68,7 @@ func (*DNSGenerator) createRecordsResources(api *cf.API, zoneID string) ([]terra
 				"domain":  record.ZoneName,
 				"name":    record.Name,
 			},
-			[]string{},
+			[]string{"proxied"},
 			map[string]interface{}{},
 		)
 
@@ -124,5 +124,25 @@ func (g *DNSGenerator) PostConvertHook() error {
 		}
 	}
 
+	// connect each record's zone_id to the cloudflare_zone resource it belongs to, so
+	// deleting/recreating a zone doesn't leave records pointing at a stale literal ID.
+	// A record whose zone wasn't imported (filtered out, or outside this run) keeps its
+	// literal zone_id.
+	for i, resourceRecord := range g.Resources {
+		if resourceRecord.InstanceInfo.Type != "cloudflare_record" {
+			continue
+		}
+		zoneID := resourceRecord.Item["zone_id"].(string)
+		for _, resourceZone := range g.Resources {
+			if resourceZone.InstanceInfo.Type != "cloudflare_zone" {
+				continue
+			}
+			if zoneID == resourceZone.InstanceState.ID {
+				g.Resources[i].Item["zone_id"] = "${cloudflare_zone." + resourceZone.ResourceName + ".id}"
+				break
+			}
+		}
+	}
+
 	return nil
 }
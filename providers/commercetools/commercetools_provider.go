@@ -35,13 +35,7 @@ func (p CommercetoolsProvider) GetResourceConnections() map[string]map[string][]
 }
 
 func (p CommercetoolsProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"commercetools": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
 
 // Init CommerectoolsProvider
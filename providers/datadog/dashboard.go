@@ -23,8 +23,11 @@ import (
 )
 
 var (
-	// DashboardAllowEmptyValues ...
-	DashboardAllowEmptyValues = []string{"tags."}
+	// DashboardAllowEmptyValues covers tags plus the optional per-type fields on
+	// widget definitions (e.g. a timeseries widget's "style" vs a note widget's
+	// "content"), so a widget of one type isn't pruned for lacking another type's
+	// fields.
+	DashboardAllowEmptyValues = []string{"tags.", "widget."}
 )
 
 // DashboardGenerator ...
@@ -50,10 +53,16 @@ func (DashboardGenerator) createResources(dashboards []datadog.BoardLite) []terr
 
 // InitResources Generate TerraformResources from Datadog API,
 // from each dashboard create 1 TerraformResource.
-// Need Dashboard ID as ID for terraform resource
+// Need Dashboard ID as ID for terraform resource.
+// GetBoards only returns summaries, but that's all createResources needs: the full
+// widget-level definition is populated later when the provider refreshes each
+// resource by ID, the same way every other generator in this package works.
 func (g *DashboardGenerator) InitResources() error {
-	client := datadog.NewClient(g.Args["api-key"].(string), g.Args["app-key"].(string))
-	_, err := client.Validate()
+	client, err := g.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Validate()
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,8 @@ import (
 	"errors"
 	"os"
 
+	datadog "github.com/zorkian/go-datadog-api"
+
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils/provider_wrapper"
 	"github.com/zclconf/go-cty/cty"
@@ -27,6 +29,15 @@ type DatadogProvider struct {
 	terraform_utils.Provider
 	apiKey string
 	appKey string
+	// tags is an optional comma-separated list passed to the monitor generator so it
+	// can filter at the API level instead of importing everything and filtering after.
+	tags string
+	// site selects which Datadog site the API client talks to (e.g. "datadoghq.eu").
+	// Defaults to the US site when empty.
+	site string
+	// client is built once, on the first InitService call, and reused for every
+	// resource type imported in this run; see InitService.
+	client *datadog.Client
 }
 
 // Init check env params
@@ -51,6 +62,14 @@ func (p *DatadogProvider) Init(args []string) error {
 		}
 	}
 
+	if len(args) > 2 {
+		p.tags = args[2]
+	}
+
+	if len(args) > 3 {
+		p.site = args[3]
+	}
+
 	return nil
 }
 
@@ -73,12 +92,31 @@ func (p *DatadogProvider) InitService(serviceName string) error {
 	if _, isSupported = p.GetSupportedService()[serviceName]; !isSupported {
 		return errors.New(p.GetName() + ": " + serviceName + " not supported service")
 	}
+	// Built once and reused across every InitService call in this run, so every
+	// generator shares one client instead of each re-deriving it. DatadogService.Client
+	// only builds its own if this arg is missing, which is what happens when a service
+	// is used without going through this provider (e.g. directly via
+	// terraform_utils.RegisterProvider).
+	if p.client == nil {
+		client, err := newDatadogClient(map[string]interface{}{
+			"api-key": p.apiKey,
+			"app-key": p.appKey,
+			"site":    p.site,
+		})
+		if err != nil {
+			return err
+		}
+		p.client = client
+	}
 	p.Service = p.GetSupportedService()[serviceName]
 	p.Service.SetName(serviceName)
 	p.Service.SetProviderName(p.GetName())
 	p.Service.SetArgs(map[string]interface{}{
 		"api-key": p.apiKey,
 		"app-key": p.appKey,
+		"tags":    p.tags,
+		"site":    p.site,
+		"client":  p.client,
 	})
 	return nil
 }
@@ -86,13 +124,17 @@ func (p *DatadogProvider) InitService(serviceName string) error {
 // GetSupportedService return map of support service for Datadog
 func (p *DatadogProvider) GetSupportedService() map[string]terraform_utils.ServiceGenerator {
 	return map[string]terraform_utils.ServiceGenerator{
-		"dashboard":   &DashboardGenerator{},
-		"downtime":    &DowntimeGenerator{},
-		"monitor":     &MonitorGenerator{},
-		"screenboard": &ScreenboardGenerator{},
-		"synthetics":  &SyntheticsGenerator{},
-		"timeboard":   &TimeboardGenerator{},
-		"user":        &UserGenerator{},
+		"dashboard":                      &DashboardGenerator{},
+		"downtime":                       &DowntimeGenerator{},
+		"integration_aws_log_collection": &IntegrationAWSLogCollectionGenerator{},
+		"logs_index":                     &LogsIndexGenerator{},
+		"logs_pipeline":                  &LogsPipelineGenerator{},
+		"monitor":                        &MonitorGenerator{},
+		"screenboard":                    &ScreenboardGenerator{},
+		"slo":                            &SLOGenerator{},
+		"synthetics":                     &SyntheticsGenerator{},
+		"timeboard":                      &TimeboardGenerator{},
+		"user":                           &UserGenerator{},
 	}
 }
 
@@ -103,11 +145,5 @@ func (DatadogProvider) GetResourceConnections() map[string]map[string][]string {
 
 // GetProviderData return map of provider data for Datadog
 func (p DatadogProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			p.GetName(): map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
@@ -14,8 +14,119 @@
 
 package datadog
 
-import "github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+import (
+	"fmt"
+	"os"
+
+	datadog "github.com/zorkian/go-datadog-api"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
 
 type DatadogService struct {
 	terraform_utils.Service
 }
+
+// envAPIKey, envAppKey, and envSite are the standard Datadog environment variable
+// names (the same ones the official Terraform provider and datadog-ci read), used by
+// Client as a fallback for a generator run outside the "datadog" CLI provider (e.g. via
+// terraform_utils.RegisterProvider) that never populated Args itself.
+const (
+	envAPIKey = "DD_API_KEY"
+	envAppKey = "DD_APP_KEY"
+	envSite   = "DD_SITE"
+)
+
+// Client returns this service's Datadog API client. DatadogProvider.InitService builds
+// one client per run and passes it in Args["client"], so every generator's
+// InitResources shares it; Client only builds (and caches into Args, for the lifetime
+// of this one service) if it's missing, which happens when a service is used without
+// going through DatadogProvider (e.g. directly via terraform_utils.RegisterProvider).
+// Args values set by the provider (e.g. from CLI flags) take precedence;
+// DD_API_KEY/DD_APP_KEY/DD_SITE only fill in whatever Args left unset.
+//
+// TODO: this only builds the v1 (zorkian) REST client newDatadogClient wraps; there's
+// no vendored datadog-api-client-go in this tree yet, so a datadogClientV2 (needed for
+// endpoints v1 doesn't cover, e.g. some SLO and logs-pipeline operations) isn't built.
+func (s *DatadogService) Client() (*datadog.Client, error) {
+	args := s.GetArgs()
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	if client, ok := args["client"].(*datadog.Client); ok {
+		return client, nil
+	}
+	setArgFromEnvIfUnset(args, "api-key", envAPIKey)
+	setArgFromEnvIfUnset(args, "app-key", envAppKey)
+	setArgFromEnvIfUnset(args, "site", envSite)
+
+	client, err := newDatadogClient(args)
+	if err != nil {
+		return nil, err
+	}
+	args["client"] = client
+	s.SetArgs(args)
+	return client, nil
+}
+
+// setArgFromEnvIfUnset fills args[argKey] from the environment variable envKey, unless
+// args already has a non-empty value for argKey.
+func setArgFromEnvIfUnset(args map[string]interface{}, argKey, envKey string) {
+	if v, _ := args[argKey].(string); v != "" {
+		return
+	}
+	if v := os.Getenv(envKey); v != "" {
+		args[argKey] = v
+	}
+}
+
+// datadogSiteHosts maps the Datadog site names users are told to pass (matching the
+// provider's own "site" argument) to the API host backing them. US is the default.
+var datadogSiteHosts = map[string]string{
+	"datadoghq.com":     "https://api.datadoghq.com",
+	"datadoghq.eu":      "https://api.datadoghq.eu",
+	"us3.datadoghq.com": "https://api.us3.datadoghq.com",
+	"us5.datadoghq.com": "https://api.us5.datadoghq.com",
+	"ddog-gov.com":      "https://api.ddog-gov.com",
+}
+
+// newDatadogClient builds a client from the generator's api-key/app-key args, pointed
+// at the site named in the optional "site" arg (default: US). An unrecognized site
+// fails fast instead of silently hitting the wrong host.
+func newDatadogClient(args map[string]interface{}) (*datadog.Client, error) {
+	client := datadog.NewClient(args["api-key"].(string), args["app-key"].(string))
+	site, _ := args["site"].(string)
+	if site == "" {
+		return client, nil
+	}
+	host, ok := datadogSiteHosts[site]
+	if !ok {
+		return nil, fmt.Errorf("unknown datadog site %q", site)
+	}
+	client.SetBaseUrl(host)
+	return client, nil
+}
+
+// PageFetcher fetches a single page of at most limit items starting at offset,
+// returning how many items that page actually contained.
+type PageFetcher func(limit, offset int) (int, error)
+
+// Paginate repeatedly calls fetch, advancing offset by pageSize each time, until a
+// page comes back with fewer than pageSize items (i.e. the last page). It's meant for
+// Datadog list endpoints that cap page size via limit/offset, such as
+// SearchServiceLevelObjectives, so a generator doesn't silently stop at the first page
+// in a large org.
+func (DatadogService) Paginate(pageSize int, fetch PageFetcher) error {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	for offset := 0; ; offset += pageSize {
+		count, err := fetch(pageSize, offset)
+		if err != nil {
+			return err
+		}
+		if count < pageSize {
+			return nil
+		}
+	}
+}
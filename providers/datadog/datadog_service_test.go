@@ -0,0 +1,80 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPaginateCollectsEveryPage(t *testing.T) {
+	total := 25
+	pageSize := 10
+	var seen []int
+
+	service := DatadogService{}
+	err := service.Paginate(pageSize, func(limit, offset int) (int, error) {
+		remaining := total - offset
+		if remaining <= 0 {
+			return 0, nil
+		}
+		count := limit
+		if remaining < count {
+			count = remaining
+		}
+		for i := 0; i < count; i++ {
+			seen = append(seen, offset+i)
+		}
+		return count, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(seen) != total {
+		t.Fatalf("expected %d items across all pages, got %d", total, len(seen))
+	}
+}
+
+func TestClientFallsBackToEnvVarsAndCaches(t *testing.T) {
+	for k, v := range map[string]string{
+		envAPIKey: "env-api-key",
+		envAppKey: "env-app-key",
+	} {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	service := &DatadogService{}
+	service.Args = map[string]interface{}{}
+
+	client, err := service.Client()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := service.Args["api-key"], "env-api-key"; got != want {
+		t.Errorf("Args[api-key] = %v, want %q", got, want)
+	}
+	if got, want := service.Args["app-key"], "env-app-key"; got != want {
+		t.Errorf("Args[app-key] = %v, want %q", got, want)
+	}
+
+	cached, err := service.Client()
+	if err != nil {
+		t.Fatalf("unexpected error on second call: %s", err)
+	}
+	if cached != client {
+		t.Error("Client should cache and return the same client on subsequent calls")
+	}
+}
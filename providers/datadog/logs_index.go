@@ -0,0 +1,78 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"fmt"
+
+	datadog "github.com/zorkian/go-datadog-api"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+var (
+	// LogsIndexAllowEmptyValues ...
+	LogsIndexAllowEmptyValues = []string{"exclusion_filter.", "filter."}
+)
+
+// LogsIndexGenerator ...
+type LogsIndexGenerator struct {
+	DatadogService
+}
+
+func (LogsIndexGenerator) createResources(indexes []*datadog.LogsIndex) []terraform_utils.Resource {
+	resources := []terraform_utils.Resource{}
+	for _, index := range indexes {
+		resourceName := index.GetName()
+		resources = append(resources, terraform_utils.NewSimpleResource(
+			resourceName,
+			fmt.Sprintf("logs_index_%s", resourceName),
+			"datadog_logs_index",
+			"datadog",
+			LogsIndexAllowEmptyValues,
+		))
+	}
+
+	return resources
+}
+
+// InitResources Generate TerraformResources from Datadog API,
+// from each logs index create 1 TerraformResource.
+// Need Index name as ID for terraform resource, since the Logs Index API is
+// keyed by name rather than a numeric ID.
+func (g *LogsIndexGenerator) InitResources() error {
+	client, err := g.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Validate()
+	if err != nil {
+		return err
+	}
+	indexList, err := client.GetLogsIndexList()
+	if err != nil {
+		return err
+	}
+	indexes := []*datadog.LogsIndex{}
+	for _, name := range indexList.IndexNames {
+		index, err := client.GetLogsIndex(name)
+		if err != nil {
+			return err
+		}
+		indexes = append(indexes, index)
+	}
+	g.Resources = g.createResources(indexes)
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"fmt"
+
+	datadog "github.com/zorkian/go-datadog-api"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+var (
+	// LogsPipelineAllowEmptyValues ...
+	LogsPipelineAllowEmptyValues = []string{"tags."}
+)
+
+// LogsPipelineGenerator ...
+type LogsPipelineGenerator struct {
+	DatadogService
+}
+
+func (LogsPipelineGenerator) createResources(pipelines []*datadog.LogsPipeline) []terraform_utils.Resource {
+	resources := []terraform_utils.Resource{}
+	for _, pipeline := range pipelines {
+		resourceName := pipeline.GetId()
+		resources = append(resources, terraform_utils.NewSimpleResource(
+			resourceName,
+			fmt.Sprintf("logs_pipeline_%s", resourceName),
+			"datadog_logs_custom_pipeline",
+			"datadog",
+			LogsPipelineAllowEmptyValues,
+		))
+	}
+
+	return resources
+}
+
+// InitResources Generate TerraformResources from Datadog API,
+// from each logs pipeline create 1 TerraformResource.
+// Need Pipeline ID as ID for terraform resource
+func (g *LogsPipelineGenerator) InitResources() error {
+	client, err := g.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Validate()
+	if err != nil {
+		return err
+	}
+	pipelineList, err := client.GetLogsPipelineList()
+	if err != nil {
+		return err
+	}
+	pipelines := []*datadog.LogsPipeline{}
+	for _, id := range pipelineList.PipelineIds {
+		pipeline, err := client.GetLogsPipeline(id)
+		if err != nil {
+			return err
+		}
+		pipelines = append(pipelines, pipeline)
+	}
+	g.Resources = g.createResources(pipelines)
+	return nil
+}
@@ -16,31 +16,70 @@ package datadog
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
 
+	"github.com/zclconf/go-cty/cty"
 	datadog "github.com/zorkian/go-datadog-api"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
 )
 
+// monitorIDPattern matches the literal numeric monitor IDs Datadog embeds in a
+// composite monitor's query, e.g. "12345 && 67890".
+var monitorIDPattern = regexp.MustCompile(`\d+`)
+
 var (
 	// MonitorAllowEmptyValues ...
 	MonitorAllowEmptyValues = []string{"tags."}
 )
 
+// supportedMonitorTypes lists the monitor "type" values the typed datadog_monitor
+// Terraform resource can round-trip cleanly. A monitor whose type isn't here (e.g.
+// newer alert types like audit or error-tracking) gets mangled by datadog_monitor's
+// schema and is imported as datadog_monitor_json instead, carrying its raw JSON
+// definition rather than trying to force it into the typed schema.
+var supportedMonitorTypes = map[string]bool{
+	"composite":             true,
+	"event alert":           true,
+	"event-v2 alert":        true,
+	"log alert":             true,
+	"metric alert":          true,
+	"process alert":         true,
+	"query alert":           true,
+	"rum alert":             true,
+	"service check":         true,
+	"slo alert":             true,
+	"trace-analytics alert": true,
+}
+
 // MonitorGenerator ...
 type MonitorGenerator struct {
 	DatadogService
+
+	// compositeMonitorIDs tracks which imported monitors are of type "composite", so
+	// PostConvertHook only rewrites their query and leaves ordinary monitors' queries
+	// (which can legitimately contain unrelated numbers) untouched.
+	compositeMonitorIDs map[string]bool
 }
 
-func (MonitorGenerator) createResources(monitors []datadog.Monitor) []terraform_utils.Resource {
+func (g *MonitorGenerator) createResources(monitors []datadog.Monitor) []terraform_utils.Resource {
+	g.compositeMonitorIDs = map[string]bool{}
 	resources := []terraform_utils.Resource{}
 	for _, monitor := range monitors {
 		resourceName := strconv.Itoa(monitor.GetId())
+		if monitor.GetType() == "composite" {
+			g.compositeMonitorIDs[resourceName] = true
+		}
+		resourceType := "datadog_monitor"
+		if !supportedMonitorTypes[monitor.GetType()] {
+			resourceType = "datadog_monitor_json"
+		}
 		resources = append(resources, terraform_utils.NewSimpleResource(
 			resourceName,
 			fmt.Sprintf("monitor_%s", resourceName),
-			"datadog_monitor",
+			resourceType,
 			"datadog",
 			MonitorAllowEmptyValues,
 		))
@@ -53,15 +92,64 @@ func (MonitorGenerator) createResources(monitors []datadog.Monitor) []terraform_
 // from each monitor create 1 TerraformResource.
 // Need Monitor ID as ID for terraform resource
 func (g *MonitorGenerator) InitResources() error {
-	client := datadog.NewClient(g.Args["api-key"].(string), g.Args["app-key"].(string))
-	_, err := client.Validate()
+	client, err := g.Client()
 	if err != nil {
 		return err
 	}
-	monitors, err := client.GetMonitors()
+	_, err = client.Validate()
+	if err != nil {
+		return err
+	}
+	monitors, err := g.getMonitors(client)
 	if err != nil {
 		return err
 	}
 	g.Resources = g.createResources(monitors)
 	return nil
 }
+
+// getMonitors lists monitors, restricting the API call itself to the tags in the
+// generator's "tags" arg (a comma-separated list) when one was supplied. Filtering
+// server-side avoids fetching and refreshing monitors the caller doesn't want, unlike
+// post-fetch ResourceFilter filtering.
+func (g *MonitorGenerator) getMonitors(client *datadog.Client) ([]datadog.Monitor, error) {
+	tags, _ := g.Args["tags"].(string)
+	if tags == "" {
+		return client.GetMonitors()
+	}
+	return client.GetMonitorsWithOptions(datadog.MonitorQueryOpts{MonitorTags: strings.Split(tags, ",")})
+}
+
+// PostConvertHook rewrites composite monitors' "query" attribute so literal monitor
+// IDs that were also imported (e.g. "12345 && 67890") reference the corresponding
+// datadog_monitor resource instead, making the generated config portable across
+// accounts. IDs that weren't imported are left as literals.
+func (g *MonitorGenerator) PostConvertHook() error {
+	idToAddress := map[string]string{}
+	for _, resource := range g.Resources {
+		idToAddress[resource.InstanceState.ID] = resource.Address()
+	}
+
+	for i := range g.Resources {
+		resource := &g.Resources[i]
+		if !g.compositeMonitorIDs[resource.InstanceState.ID] {
+			continue
+		}
+		query, ok := resource.GetStateAttr("query")
+		if !ok {
+			continue
+		}
+		rewritten := monitorIDPattern.ReplaceAllStringFunc(query, func(id string) string {
+			address, imported := idToAddress[id]
+			if !imported {
+				return id
+			}
+			return fmt.Sprintf("${%s.id}", address)
+		})
+		if rewritten != query {
+			resource.SetStateAttrPath(cty.StringVal(rewritten), "query")
+		}
+	}
+
+	return g.Service.PostConvertHook()
+}
@@ -0,0 +1,40 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"testing"
+
+	datadog "github.com/zorkian/go-datadog-api"
+)
+
+func TestCreateResourcesFallsBackToJSONForUnsupportedType(t *testing.T) {
+	metricType := "metric alert"
+	auditType := "audit alert"
+	monitors := []datadog.Monitor{
+		{Id: datadog.Int(1), Type: &metricType},
+		{Id: datadog.Int(2), Type: &auditType},
+	}
+
+	g := &MonitorGenerator{}
+	resources := g.createResources(monitors)
+
+	if got, want := resources[0].InstanceInfo.Type, "datadog_monitor"; got != want {
+		t.Errorf("supported type: InstanceInfo.Type = %q, want %q", got, want)
+	}
+	if got, want := resources[1].InstanceInfo.Type, "datadog_monitor_json"; got != want {
+		t.Errorf("unsupported type: InstanceInfo.Type = %q, want %q", got, want)
+	}
+}
@@ -53,8 +53,11 @@ func (ScreenboardGenerator) createResources(screenboards []*datadog.ScreenboardL
 // from each screenboard create 1 TerraformResource.
 // Need Screenboard ID as ID for terraform resource
 func (g *ScreenboardGenerator) InitResources() error {
-	client := datadog.NewClient(g.Args["api-key"].(string), g.Args["app-key"].(string))
-	_, err := client.Validate()
+	client, err := g.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Validate()
 	if err != nil {
 		return err
 	}
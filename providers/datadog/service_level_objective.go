@@ -0,0 +1,103 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadog
+
+import (
+	"fmt"
+
+	datadog "github.com/zorkian/go-datadog-api"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+const sloPageSize = 100
+
+var (
+	// SLOAllowEmptyValues ...
+	SLOAllowEmptyValues = []string{"tags.", "monitor_ids.", "query."}
+)
+
+// SLOGenerator ...
+type SLOGenerator struct {
+	DatadogService
+}
+
+func (SLOGenerator) createResources(slos []*datadog.ServiceLevelObjective) []terraform_utils.Resource {
+	resources := []terraform_utils.Resource{}
+	for _, slo := range slos {
+		resourceName := terraform_utils.TfSanitize(slo.GetName())
+		resources = append(resources, terraform_utils.NewSimpleResource(
+			slo.GetID(),
+			fmt.Sprintf("slo_%s", resourceName),
+			"datadog_service_level_objective",
+			"datadog",
+			SLOAllowEmptyValues,
+		))
+	}
+
+	return resources
+}
+
+// InitResources Generate TerraformResources from Datadog API,
+// from each SLO create 1 TerraformResource. Need SLO ID as ID for terraform resource.
+// SearchServiceLevelObjectives caps its page size, so results are collected with
+// DatadogService.Paginate rather than a single call, to avoid silently dropping SLOs
+// in a large org.
+func (g *SLOGenerator) InitResources() error {
+	client, err := g.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Validate()
+	if err != nil {
+		return err
+	}
+
+	slos := []*datadog.ServiceLevelObjective{}
+	err = g.Paginate(sloPageSize, func(limit, offset int) (int, error) {
+		page, err := client.SearchServiceLevelObjectives(limit, offset, "", nil)
+		if err != nil {
+			return 0, err
+		}
+		slos = append(slos, page...)
+		return len(page), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.Resources = g.createResources(slos)
+	return nil
+}
+
+// PostConvertHook ensures a metric-based SLO doesn't carry an empty monitor_ids block
+// and a monitor-based SLO doesn't carry an empty query block, since datadog_service_
+// level_objective's schema only allows one of the two to be set.
+func (g *SLOGenerator) PostConvertHook() error {
+	for i := range g.Resources {
+		resource := &g.Resources[i]
+		sloType, ok := resource.GetStateAttr("type")
+		if !ok {
+			continue
+		}
+		if sloType == "metric" {
+			delete(resource.Item, "monitor_ids")
+		} else {
+			delete(resource.Item, "query")
+		}
+	}
+
+	return g.Service.PostConvertHook()
+}
@@ -23,8 +23,14 @@ import (
 )
 
 var (
-	// SyntheticsAllowEmptyValues ...
+	// SyntheticsAllowEmptyValues are the fields common to every synthetics test type.
 	SyntheticsAllowEmptyValues = []string{"tags."}
+	// SyntheticsAPITestAllowEmptyValues additionally covers the assertion blocks that
+	// only api tests populate, so a request with no assertions doesn't get pruned.
+	SyntheticsAPITestAllowEmptyValues = append(append([]string{}, SyntheticsAllowEmptyValues...), "config.assertions.")
+	// SyntheticsBrowserTestAllowEmptyValues additionally covers the step blocks that
+	// only browser tests populate.
+	SyntheticsBrowserTestAllowEmptyValues = append(append([]string{}, SyntheticsAllowEmptyValues...), "steps.")
 )
 
 // SyntheticsGenerator ...
@@ -36,12 +42,18 @@ func (SyntheticsGenerator) createResources(syntheticsList []datadog.SyntheticsTe
 	resources := []terraform_utils.Resource{}
 	for _, synthetics := range syntheticsList {
 		resourceName := synthetics.GetPublicId()
+		allowEmptyValues := SyntheticsAllowEmptyValues
+		if synthetics.GetType() == "browser" {
+			allowEmptyValues = SyntheticsBrowserTestAllowEmptyValues
+		} else {
+			allowEmptyValues = SyntheticsAPITestAllowEmptyValues
+		}
 		resources = append(resources, terraform_utils.NewSimpleResource(
 			resourceName,
 			fmt.Sprintf("synthetics_%s", resourceName),
 			"datadog_synthetics_test",
 			"datadog",
-			SyntheticsAllowEmptyValues,
+			allowEmptyValues,
 		))
 	}
 
@@ -52,8 +64,11 @@ func (SyntheticsGenerator) createResources(syntheticsList []datadog.SyntheticsTe
 // from each synthetics create 1 TerraformResource.
 // Need Synthetics ID as ID for terraform resource
 func (g *SyntheticsGenerator) InitResources() error {
-	client := datadog.NewClient(g.Args["api-key"].(string), g.Args["app-key"].(string))
-	_, err := client.Validate()
+	client, err := g.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Validate()
 	if err != nil {
 		return err
 	}
@@ -52,8 +52,11 @@ func (UserGenerator) createResources(users []datadog.User) []terraform_utils.Res
 // from each user create 1 TerraformResource.
 // Need User ID as ID for terraform resource
 func (g *UserGenerator) InitResources() error {
-	client := datadog.NewClient(g.Args["api-key"].(string), g.Args["app-key"].(string))
-	_, err := client.Validate()
+	client, err := g.Client()
+	if err != nil {
+		return err
+	}
+	_, err = client.Validate()
 	if err != nil {
 		return err
 	}
@@ -41,14 +41,13 @@ func (p *DigitalOceanProvider) GetName() string {
 }
 
 func (p *DigitalOceanProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"digitalocean": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-				"token":   p.token,
-			},
-		},
+	data := terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"token": terraform_utils.SecretVarRef("do_token"),
+	})
+	for k, v := range terraform_utils.SecretVariables("do_token") {
+		data[k] = v
 	}
+	return data
 }
 
 func (DigitalOceanProvider) GetResourceConnections() map[string]map[string][]string {
@@ -0,0 +1,71 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/run/v1"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+var cloudRunAllowEmptyValues = []string{"template."}
+
+type CloudRunGenerator struct {
+	GCPService
+}
+
+// Run on ServicesListCall and create a TerraformResource for each Cloud Run service.
+func (g CloudRunGenerator) createResources(servicesList *run.NamespacesServicesListCall) []terraform_utils.Resource {
+	resources := []terraform_utils.Resource{}
+	response, err := servicesList.Do()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, service := range response.Items {
+		name := service.Metadata.Name
+		resources = append(resources, terraform_utils.NewSimpleResource(
+			g.GetArgs()["project"].(string)+"/"+g.GetArgs()["region"].(compute.Region).Name+"/"+name,
+			g.GetArgs()["region"].(compute.Region).Name+"_"+name,
+			"google_cloud_run_service",
+			"google",
+			cloudRunAllowEmptyValues,
+		))
+	}
+	return resources
+}
+
+// Generate TerraformResources from GCP API,
+// from each Cloud Run service create 1 TerraformResource.
+// The import ID is <project>/<region>/<name> and the resource name <region>_<name>;
+// Cloud Run services have no self_link, so GetIDKey falls back to "id".
+func (g *CloudRunGenerator) InitResources() error {
+	ctx := context.Background()
+	runService, err := run.NewService(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	region := g.GetArgs()["region"].(compute.Region).Name
+	parent := "namespaces/" + g.GetArgs()["project"].(string)
+	runService.BasePath = "https://" + region + "-run.googleapis.com"
+	servicesList := runService.Namespaces.Services.List(parent)
+
+	g.Resources = g.createResources(servicesList)
+	return nil
+}
@@ -28,6 +28,10 @@ type GCPProvider struct {
 	terraform_utils.Provider
 	projectName string
 	region      compute.Region
+	// labelFilter is a GCP list-API filter expression (e.g. "labels.team=platform"),
+	// passed through to generators whose underlying List call supports server-side
+	// filtering, so only matching resources are refreshed.
+	labelFilter string
 }
 
 func GetRegions(project string) []string {
@@ -69,6 +73,9 @@ func (p *GCPProvider) Init(args []string) error {
 	}
 	p.projectName = projectName
 	p.region = *getRegion(projectName, args[0])
+	if len(args) > 2 {
+		p.labelFilter = args[2]
+	}
 	return nil
 }
 
@@ -85,8 +92,9 @@ func (p *GCPProvider) InitService(serviceName string) error {
 	p.Service.SetName(serviceName)
 	p.Service.SetProviderName(p.GetName())
 	p.Service.SetArgs(map[string]interface{}{
-		"region":  p.region,
-		"project": p.projectName,
+		"region":       p.region,
+		"project":      p.projectName,
+		"label_filter": p.labelFilter,
 	})
 	return nil
 }
@@ -96,6 +104,7 @@ func (p *GCPProvider) GetSupportedService() map[string]terraform_utils.ServiceGe
 	services := ComputeServices
 	services["bigQuery"] = &BigQueryGenerator{}
 	services["cloudFunctions"] = &CloudFunctionsGenerator{}
+	services["cloudRun"] = &CloudRunGenerator{}
 	services["cloudsql"] = &CloudSQLGenerator{}
 	services["dataProc"] = &DataprocGenerator{}
 	services["dns"] = &CloudDNSGenerator{}
@@ -116,6 +125,10 @@ func (GCPProvider) GetResourceConnections() map[string]map[string][]string {
 	return map[string]map[string][]string{
 		"backendBuckets": {"gcs": []string{"bucket_name", "name"}},
 		"firewalls":      {"networks": []string{"network", "self_link"}},
+		"instances": {
+			"networks":    []string{"network_interface.network", "self_link"},
+			"subnetworks": []string{"network_interface.subnetwork", "self_link"},
+		},
 		"gke": {
 			"networks":    []string{"network", "self_link"},
 			"subnetworks": []string{"subnetwork", "self_link"},
@@ -132,12 +145,7 @@ func (GCPProvider) GetResourceConnections() map[string]map[string][]string {
 }
 
 func (p GCPProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			p.GetName(): map[string]interface{}{
-				"project": p.projectName,
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"project": p.projectName,
+	})
 }
@@ -74,17 +74,7 @@ func (g *GcsGenerator) createBucketsResources(ctx context.Context, gcsService *s
 				GcsAllowEmptyValues,
 				GcsAdditionalFields,
 			))
-			resources = append(resources, terraform_utils.NewResource(
-				bucket.Name,
-				bucket.Name,
-				"google_storage_bucket_iam_binding",
-				"google",
-				map[string]string{
-					"bucket": bucket.Name,
-				},
-				GcsAllowEmptyValues,
-				GcsAdditionalFields,
-			))
+			resources = append(resources, g.createBucketIamBindingResources(ctx, gcsService, bucket)...)
 			resources = append(resources, terraform_utils.NewResource(
 				bucket.Name,
 				bucket.Name,
@@ -116,6 +106,34 @@ func (g *GcsGenerator) createBucketsResources(ctx context.Context, gcsService *s
 	return resources
 }
 
+// createBucketIamBindingResources emits one google_storage_bucket_iam_binding per role
+// bound on the bucket's IAM policy, so the members granted each role survive the
+// import instead of being lost alongside the bucket's ACLs. ImportID follows the
+// provider's "<bucket> <role>" format.
+func (g *GcsGenerator) createBucketIamBindingResources(ctx context.Context, gcsService *storage.Service, bucket *storage.Bucket) []terraform_utils.Resource {
+	resources := []terraform_utils.Resource{}
+	policy, err := gcsService.Buckets.GetIamPolicy(bucket.Name).Context(ctx).Do()
+	if err != nil {
+		log.Println(err)
+		return resources
+	}
+	for _, binding := range policy.Bindings {
+		resources = append(resources, terraform_utils.NewResource(
+			bucket.Name+" "+binding.Role,
+			bucket.Name+"_"+binding.Role,
+			"google_storage_bucket_iam_binding",
+			"google",
+			map[string]string{
+				"bucket": bucket.Name,
+				"role":   binding.Role,
+			},
+			GcsAllowEmptyValues,
+			GcsAdditionalFields,
+		))
+	}
+	return resources
+}
+
 func (g *GcsGenerator) createNotificationResources(ctx context.Context, gcsService *storage.Service, bucket *storage.Bucket) []terraform_utils.Resource {
 	resources := []terraform_utils.Resource{}
 	notificationList, err := gcsService.Notifications.List(bucket.Name).Do()
@@ -76,6 +76,9 @@ func (g *InstancesGenerator) InitResources() error {
 		t := strings.Split(zoneLink, "/")
 		zone := t[len(t)-1]
 		instancesList := computeService.Instances.List(g.GetArgs()["project"].(string), zone)
+		if labelFilter, ok := g.GetArgs()["label_filter"].(string); ok && labelFilter != "" {
+			instancesList = instancesList.Filter(labelFilter)
+		}
 		g.Resources = append(g.Resources, g.createResources(ctx, instancesList, zone)...)
 	}
 
@@ -34,14 +34,9 @@ func (p GithubProvider) GetResourceConnections() map[string]map[string][]string
 }
 
 func (p GithubProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"github": map[string]interface{}{
-				"version":      provider_wrapper.GetProviderVersion(p.GetName()),
-				"organization": p.organization,
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"organization": p.organization,
+	})
 }
 
 func (p *GithubProvider) GetConfig() cty.Value {
@@ -28,6 +28,10 @@ type RepositoriesGenerator struct {
 	GithubService
 }
 
+// required_status_checks and required_pull_request_reviews are optional blocks;
+// a branch protected without one comes back empty and would otherwise be pruned.
+var branchProtectionAllowEmptyValues = []string{"required_status_checks.", "required_pull_request_reviews."}
+
 // Generate TerraformResources from github API,
 func (g *RepositoriesGenerator) InitResources() error {
 	ctx := context.Background()
@@ -107,7 +111,7 @@ func (g *RepositoriesGenerator) createRepositoryBranchProtectionResources(ctx co
 				repo.GetName()+"_"+branch.GetName(),
 				"github_branch_protection",
 				"github",
-				[]string{},
+				branchProtectionAllowEmptyValues,
 			))
 		}
 	}
@@ -25,6 +25,11 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// TeamAllowEmptyValues lets github_team keep an empty "description" (many teams have
+// none) and "privacy" (some GitHub Enterprise setups return "" rather than "secret" for
+// legacy teams) instead of having them silently dropped as zero values.
+var TeamAllowEmptyValues = []string{"description", "privacy"}
+
 type TeamsGenerator struct {
 	GithubService
 }
@@ -37,7 +42,7 @@ func (g *TeamsGenerator) createTeamsResources(ctx context.Context, teams []*gith
 			team.GetName(),
 			"github_team",
 			"github",
-			[]string{},
+			TeamAllowEmptyValues,
 		))
 		resources = append(resources, g.createTeamMembersResources(ctx, team, client)...)
 		resources = append(resources, g.createTeamRepositoriesResources(ctx, team, client)...)
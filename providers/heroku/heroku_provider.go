@@ -47,15 +47,14 @@ func (p *HerokuProvider) GetName() string {
 }
 
 func (p *HerokuProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"heroku": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-				"email":   p.email,
-				"api_key": p.apiKey,
-			},
-		},
+	data := terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"email":   p.email,
+		"api_key": terraform_utils.SecretVarRef("heroku_api_key"),
+	})
+	for k, v := range terraform_utils.SecretVariables("heroku_api_key") {
+		data[k] = v
 	}
+	return data
 }
 
 func (HerokuProvider) GetResourceConnections() map[string]map[string][]string {
@@ -16,6 +16,7 @@ package kubernetes
 
 import (
 	"reflect"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
 
@@ -49,42 +50,54 @@ func (k *Kind) InitResources() error {
 		extractClientSetFuncGroupName(k.Group, k.Version)).Call(
 		[]reflect.Value{})[0]
 
-	param := []reflect.Value{}
-	namespace := ""
+	namespaces := []string{""}
 	if k.Namespaced {
-		param = append(param, reflect.ValueOf(namespace))
+		if raw, _ := k.GetArgs()["namespaces"].(string); raw != "" {
+			namespaces = strings.Split(raw, ",")
+		}
 	}
-	resource := group.MethodByName(extractClientSetFuncTypeName(k.Name)).Call(param)[0]
 
-	results := resource.MethodByName("List").Call([]reflect.Value{
-		reflect.ValueOf(metav1.ListOptions{})})
+	for _, namespace := range namespaces {
+		param := []reflect.Value{}
+		if k.Namespaced {
+			param = append(param, reflect.ValueOf(namespace))
+		}
+		resource := group.MethodByName(extractClientSetFuncTypeName(k.Name)).Call(param)[0]
 
-	if !results[1].IsNil() {
-		return results[1].Interface().(error)
-	}
-	items := reflect.Indirect(results[0]).FieldByName("Items")
+		results := resource.MethodByName("List").Call([]reflect.Value{
+			reflect.ValueOf(metav1.ListOptions{})})
 
-	for i := 0; i < items.Len(); i++ {
-		item := items.Index(i)
-		// Filter to resources that aren't owned by any other resource
-		if item.FieldByName("OwnerReferences").Len() > 0 {
-			continue
+		if !results[1].IsNil() {
+			return results[1].Interface().(error)
 		}
+		items := reflect.Indirect(results[0]).FieldByName("Items")
 
-		name := ""
-		if k.Namespaced {
-			name = item.FieldByName("Namespace").String() + "/" + item.FieldByName("Name").String()
-		} else {
-			name = item.FieldByName("Name").String()
-		}
+		for i := 0; i < items.Len(); i++ {
+			item := items.Index(i)
+			// Filter to resources that aren't owned by any other resource
+			if item.FieldByName("OwnerReferences").Len() > 0 {
+				continue
+			}
+
+			name := ""
+			if k.Namespaced {
+				name = item.FieldByName("Namespace").String() + "/" + item.FieldByName("Name").String()
+			} else {
+				name = item.FieldByName("Name").String()
+			}
 
-		k.Resources = append(k.Resources, terraform_utils.NewSimpleResource(
-			name,
-			name,
-			extractTfResourceName(k.Name),
-			"kubernetes",
-			[]string{},
-		))
+			k.Resources = append(k.Resources, terraform_utils.NewSimpleResource(
+				name,
+				name,
+				extractTfResourceName(k.Name),
+				"kubernetes",
+				[]string{},
+			))
+		}
+		// Cluster-scoped resources have no namespace to loop over.
+		if !k.Namespaced {
+			break
+		}
 	}
 	return nil
 }
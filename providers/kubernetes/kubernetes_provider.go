@@ -41,6 +41,9 @@ import (
 type KubernetesProvider struct {
 	terraform_utils.Provider
 	region string
+	// namespaces is a comma-separated list of namespaces to restrict Namespaced Kind
+	// generators to; empty preserves the all-namespaces behavior.
+	namespaces string
 }
 
 func (p KubernetesProvider) GetResourceConnections() map[string]map[string][]string {
@@ -48,16 +51,13 @@ func (p KubernetesProvider) GetResourceConnections() map[string]map[string][]str
 }
 
 func (p KubernetesProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"kubernetes": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
 
 func (p *KubernetesProvider) Init(args []string) error {
+	if len(args) > 0 {
+		p.namespaces = args[0]
+	}
 	return nil
 }
 
@@ -73,6 +73,9 @@ func (p *KubernetesProvider) InitService(serviceName string) error {
 	p.Service = p.GetSupportedService()[serviceName]
 	p.Service.SetName(serviceName)
 	p.Service.SetProviderName(p.GetName())
+	p.Service.SetArgs(map[string]interface{}{
+		"namespaces": p.namespaces,
+	})
 	return nil
 }
 
@@ -101,7 +104,7 @@ func (p *KubernetesProvider) GetSupportedService() map[string]terraform_utils.Se
 		log.Println(err)
 		return resources
 	}
-	resp := provider.Provider.GetSchema()
+	resp := provider.GetSchema()
 	for _, list := range lists {
 		if len(list.APIResources) == 0 {
 			continue
@@ -41,14 +41,13 @@ func (p *LinodeProvider) GetName() string {
 }
 
 func (p *LinodeProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"linode": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-				"token":   p.token,
-			},
-		},
+	data := terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"token": terraform_utils.SecretVarRef("linode_token"),
+	})
+	for k, v := range terraform_utils.SecretVariables("linode_token") {
+		data[k] = v
 	}
+	return data
 }
 
 func (LinodeProvider) GetResourceConnections() map[string]map[string][]string {
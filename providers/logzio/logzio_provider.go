@@ -41,13 +41,7 @@ func (p LogzioProvider) GetResourceConnections() map[string]map[string][]string
 }
 
 func (p LogzioProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"logzio": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
 
 func (p *LogzioProvider) GetConfig() cty.Value {
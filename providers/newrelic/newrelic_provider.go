@@ -34,13 +34,7 @@ func (p *NewRelicProvider) GetName() string {
 }
 
 func (p *NewRelicProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"newrelic": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
 
 func (NewRelicProvider) GetResourceConnections() map[string]map[string][]string {
@@ -32,14 +32,9 @@ func (p OpenStackProvider) GetResourceConnections() map[string]map[string][]stri
 }
 
 func (p OpenStackProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"openstack": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-				"region":  p.region,
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"region": p.region,
+	})
 }
 
 // check projectName in env params
@@ -41,13 +41,7 @@ func (p *RBTProvider) GetName() string {
 }
 
 func (p *RBTProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			p.GetName(): map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-			},
-		},
-	}
+	return terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{})
 }
 
 func (p *RBTProvider) GetConfig() cty.Value {
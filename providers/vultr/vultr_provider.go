@@ -41,14 +41,13 @@ func (p *VultrProvider) GetName() string {
 }
 
 func (p *VultrProvider) GetProviderData(arg ...string) map[string]interface{} {
-	return map[string]interface{}{
-		"provider": map[string]interface{}{
-			"vultr": map[string]interface{}{
-				"version": provider_wrapper.GetProviderVersion(p.GetName()),
-				"api_key": p.apiKey,
-			},
-		},
+	data := terraform_utils.ProviderData(p.GetName(), provider_wrapper.GetProviderVersion(p.GetName()), map[string]interface{}{
+		"api_key": terraform_utils.SecretVarRef("vultr_api_key"),
+	})
+	for k, v := range terraform_utils.SecretVariables("vultr_api_key") {
+		data[k] = v
 	}
+	return data
 }
 
 func (VultrProvider) GetResourceConnections() map[string]map[string][]string {
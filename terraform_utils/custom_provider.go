@@ -0,0 +1,98 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform_utils
+
+import (
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// CustomProvider is a ProviderGenerator backed by a caller-supplied registry of
+// service generator factories, instead of a providers/<name> package hand-written
+// against a specific SDK. RegisterProvider builds one, so a program that imports
+// terraform_utils can drive cmd.Import for its own resources without forking
+// terraformer to add a providers/ package: it only has to implement the existing
+// ServiceGenerator contract (InitResources/PostConvertHook/GetResources) already used
+// by every built-in generator.
+type CustomProvider struct {
+	Provider
+	name             string
+	config           cty.Value
+	supportedService map[string]func() ServiceGenerator
+}
+
+// RegisterProvider returns a ProviderGenerator named name whose supported services are
+// built from services, one factory per service name, called fresh on each InitService
+// so a service's state isn't shared across import runs.
+func RegisterProvider(name string, services map[string]func() ServiceGenerator) *CustomProvider {
+	return &CustomProvider{name: name, config: cty.ObjectVal(map[string]cty.Value{}), supportedService: services}
+}
+
+// SetConfig overrides the cty.Value GetConfig/GetBasicConfig return, e.g. so
+// PopulateIgnoreKeys can resolve provider-specific ignore rules the same way a
+// built-in provider's schema-derived config would.
+func (p *CustomProvider) SetConfig(config cty.Value) {
+	p.config = config
+}
+
+func (p *CustomProvider) Init(args []string) error {
+	return nil
+}
+
+func (p *CustomProvider) GetName() string {
+	return p.name
+}
+
+func (p *CustomProvider) GetConfig() cty.Value {
+	return p.config
+}
+
+func (p *CustomProvider) GetBasicConfig() cty.Value {
+	return p.config
+}
+
+func (p *CustomProvider) GetResourceConnections() map[string]map[string][]string {
+	return map[string]map[string][]string{}
+}
+
+func (p *CustomProvider) GetProviderData(arg ...string) map[string]interface{} {
+	return ProviderData(p.name, "", map[string]interface{}{})
+}
+
+func (p *CustomProvider) GenerateOutputPath() error {
+	return nil
+}
+
+func (p *CustomProvider) GenerateFiles() {
+}
+
+func (p *CustomProvider) GetSupportedService() map[string]ServiceGenerator {
+	resolved := make(map[string]ServiceGenerator, len(p.supportedService))
+	for name, factory := range p.supportedService {
+		resolved[name] = factory()
+	}
+	return resolved
+}
+
+func (p *CustomProvider) InitService(serviceName string) error {
+	factory, isSupported := p.supportedService[serviceName]
+	if !isSupported {
+		return errors.New(p.name + ": " + serviceName + " not supported service")
+	}
+	p.Service = factory()
+	p.Service.SetName(serviceName)
+	p.Service.SetProviderName(p.name)
+	return nil
+}
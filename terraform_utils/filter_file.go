@@ -0,0 +1,75 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform_utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FilterFileEntry mirrors ResourceFilter's exported fields, for decoding a filter
+// definition out of a user-supplied file. It's a distinct type, not ResourceFilter
+// itself, since ResourceFilter also carries compiled regex/comparison state that
+// compileValueRegexps/compileComparisons populate (LoadFilters and ParseFilter both
+// call them).
+type FilterFileEntry struct {
+	ResourceName     string   `yaml:"resource_name" json:"resource_name"`
+	FieldPath        string   `yaml:"field_path" json:"field_path"`
+	AcceptableValues []string `yaml:"acceptable_values" json:"acceptable_values"`
+	Negate           bool     `yaml:"negate" json:"negate"`
+}
+
+// LoadFilters reads a list of FilterFileEntry values from a YAML or JSON file
+// (detected by a ".json" extension; anything else is parsed as YAML, which is a
+// superset of JSON) into ResourceFilter structs, so a long list of filters can be
+// versioned instead of passed as repeated --filter flags.
+func LoadFilters(path string) ([]ResourceFilter, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []FilterFileEntry
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(data, &entries)
+	} else {
+		err = yaml.Unmarshal(data, &entries)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing filter file %s: %s", path, err)
+	}
+
+	filters := make([]ResourceFilter, 0, len(entries))
+	for i, entry := range entries {
+		filter := ResourceFilter{
+			ResourceName:     entry.ResourceName,
+			FieldPath:        entry.FieldPath,
+			AcceptableValues: entry.AcceptableValues,
+			Negate:           entry.Negate,
+		}
+		if err := filter.compileValueRegexps(); err != nil {
+			return nil, fmt.Errorf("filter file %s entry %d: %s", path, i, err)
+		}
+		if err := filter.compileComparisons(); err != nil {
+			return nil, fmt.Errorf("filter file %s entry %d: %s", path, i, err)
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
@@ -0,0 +1,93 @@
+package terraform_utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFilterFile(t *testing.T, name, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "terraformer-filter-file")
+	if err != nil {
+		t.Fatalf("TempDir() error = %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), os.ModePerm); err != nil {
+		t.Fatalf("WriteFile() error = %s", err)
+	}
+	return path
+}
+
+func TestLoadFiltersYAML(t *testing.T) {
+	path := writeFilterFile(t, "filters.yaml", `
+- resource_name: aws_instance
+  field_path: tags.Environment
+  acceptable_values: ["prod"]
+  negate: false
+- resource_name: aws_vpc
+  field_path: id
+  acceptable_values: ["vpc-1", "vpc-2"]
+  negate: true
+`)
+
+	filters, err := LoadFilters(path)
+	if err != nil {
+		t.Fatalf("LoadFilters() error = %s", err)
+	}
+
+	want := []ResourceFilter{
+		{ResourceName: "aws_instance", FieldPath: "tags.Environment", AcceptableValues: []string{"prod"}},
+		{ResourceName: "aws_vpc", FieldPath: "id", AcceptableValues: []string{"vpc-1", "vpc-2"}, Negate: true},
+	}
+	for i := range filters {
+		filters[i].ApplicableFilter = nil
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Fatalf("LoadFilters() = %#v, want %#v", filters, want)
+	}
+}
+
+func TestLoadFiltersJSON(t *testing.T) {
+	path := writeFilterFile(t, "filters.json", `[
+		{"resource_name": "aws_instance", "field_path": "id", "acceptable_values": [">=2"]}
+	]`)
+
+	filters, err := LoadFilters(path)
+	if err != nil {
+		t.Fatalf("LoadFilters() error = %s", err)
+	}
+	if len(filters) != 1 || filters[0].ResourceName != "aws_instance" {
+		t.Fatalf("LoadFilters() = %#v", filters)
+	}
+}
+
+func TestLoadFiltersInvalidRegexNamesOffendingEntry(t *testing.T) {
+	path := writeFilterFile(t, "filters.yaml", `
+- resource_name: aws_instance
+  field_path: tags.Environment
+  acceptable_values: ["prod"]
+- resource_name: aws_vpc
+  field_path: id
+  acceptable_values: ["~("]
+`)
+
+	_, err := LoadFilters(path)
+	if err == nil {
+		t.Fatal("LoadFilters() expected error, got nil")
+	}
+	if want := "entry 1"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("LoadFilters() error = %q, want it to mention %q", err.Error(), want)
+	}
+}
+
+func TestLoadFiltersMissingFile(t *testing.T) {
+	if _, err := LoadFilters("/does/not/exist.yaml"); err == nil {
+		t.Fatal("LoadFilters() expected error for missing file, got nil")
+	}
+}
@@ -347,6 +347,14 @@ func (p *FlatmapParser) fromFlatmapSet(prefix string, ty cty.Type) ([]interface{
 	return values, nil
 }
 
+// isAttributeIgnored checks name, the fully-qualified dotted flatmap key (e.g.
+// "network_interface.0.network_interface_id"), against every IgnoreKeys pattern.
+// Patterns are unanchored regexes, so a wildcard path like
+// "network_interface.*.network_interface_id" already matches every repeated block; a
+// pattern that only matches the block's own key (e.g. "^network_interface$" combined
+// with its ".#" count key) drops the whole block. Because ignored attributes are simply
+// left out of the parsed result rather than spliced out of a slice, list indices are
+// never disturbed.
 func (p *FlatmapParser) isAttributeIgnored(name string) bool {
 	ignored := false
 	for _, pattern := range p.ignoreKeys {
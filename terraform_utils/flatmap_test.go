@@ -0,0 +1,69 @@
+package terraform_utils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestIgnoreKeysMatchesNestedWildcardPaths(t *testing.T) {
+	attributes := map[string]string{
+		"network_interface.#":                     "2",
+		"network_interface.0.network_interface_id": "eni-1",
+		"network_interface.0.device_index":         "0",
+		"network_interface.1.network_interface_id": "eni-2",
+		"network_interface.1.device_index":         "1",
+	}
+	ignoreKeys := []*regexp.Regexp{regexp.MustCompile(`network_interface\.\d+\.network_interface_id`)}
+	parser := NewFlatmapParser(attributes, ignoreKeys, nil)
+
+	niType := cty.Object(map[string]cty.Type{
+		"network_interface_id": cty.String,
+		"device_index":         cty.Number,
+	})
+	ty := cty.Object(map[string]cty.Type{"network_interface": cty.List(niType)})
+
+	result, err := parser.Parse(ty)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	nis, ok := result["network_interface"].([]interface{})
+	if !ok || len(nis) != 2 {
+		t.Fatalf("expected 2 network interfaces to survive, got %v", result["network_interface"])
+	}
+	for i, ni := range nis {
+		m, ok := ni.(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected network_interface[%d] to be a map, got %v", i, ni)
+		}
+		if _, exists := m["network_interface_id"]; exists {
+			t.Errorf("expected network_interface_id to be ignored on element %d, got %v", i, m)
+		}
+		if _, exists := m["device_index"]; !exists {
+			t.Errorf("expected device_index to survive on element %d, got %v", i, m)
+		}
+	}
+}
+
+func TestAllowEmptyValuesMatchesRepeatedNestedBlocks(t *testing.T) {
+	attributes := map[string]string{
+		"rule.#":        "2",
+		"rule.0.action": "",
+		"rule.1.action": "",
+	}
+	allowEmptyValues := []*regexp.Regexp{regexp.MustCompile(`rule\.\d+\.action`)}
+	parser := NewFlatmapParser(attributes, nil, allowEmptyValues)
+
+	ruleType := cty.Object(map[string]cty.Type{"action": cty.String})
+	ty := cty.Object(map[string]cty.Type{"rule": cty.List(ruleType)})
+
+	result, err := parser.Parse(ty)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rules, ok := result["rule"].([]interface{})
+	if !ok || len(rules) != 2 {
+		t.Fatalf("expected 2 rules to survive empty-value pruning, got %v", result["rule"])
+	}
+}
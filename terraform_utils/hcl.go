@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl2/hclwrite"
@@ -161,6 +162,14 @@ func HclPrint(data interface{}, mapsObjects map[string]struct{}) ([]byte, error)
 	formatted, err = hclPrinter.Format([]byte(s))
 	// hack for support terraform 0.12
 	formatted = terraform12Adjustments(formatted, mapsObjects)
+	// depends_on takes a list of bare resource references (e.g. aws_iam_role.foo), not
+	// strings; the legacy HCL1 parser above only accepts a depends_on list as quoted
+	// strings, so the quotes come off afterward as a text pass, once nothing will
+	// re-parse it.
+	formatted = dependsOnUnquote(formatted)
+	// ignore_changes takes bare attribute references the same way depends_on takes bare
+	// resource references, so it needs the same after-the-fact unquoting.
+	formatted = ignoreChangesUnquote(formatted)
 	if err != nil {
 		log.Println("Invalid HCL follows:")
 		for i, line := range strings.Split(s, "\n") {
@@ -191,17 +200,97 @@ func terraform12Adjustments(formatted []byte, mapsObjects map[string]struct{}) [
 	return []byte(s)
 }
 
+// dependsOnUnquoteRe matches a depends_on list, quoted strings and all, across
+// however many lines the formatter split it onto.
+var dependsOnUnquoteRe = regexp.MustCompile(`(?s)depends_on = \[.*?\]`)
+
+// dependsOnStringRe matches a single quoted list element within a depends_on list.
+var dependsOnStringRe = regexp.MustCompile(`"([^"]*)"`)
+
+func dependsOnUnquote(formatted []byte) []byte {
+	return dependsOnUnquoteRe.ReplaceAllFunc(formatted, func(match []byte) []byte {
+		return dependsOnStringRe.ReplaceAll(match, []byte("$1"))
+	})
+}
+
+// ignoreChangesUnquoteRe matches a lifecycle block's ignore_changes list, quoted
+// strings and all, across however many lines the formatter split it onto.
+var ignoreChangesUnquoteRe = regexp.MustCompile(`(?s)ignore_changes = \[.*?\]`)
+
+func ignoreChangesUnquote(formatted []byte) []byte {
+	return ignoreChangesUnquoteRe.ReplaceAllFunc(formatted, func(match []byte) []byte {
+		return dependsOnStringRe.ReplaceAll(match, []byte("$1"))
+	})
+}
+
 func escapeRune(s string) string {
 	return fmt.Sprintf("-%04X-", s)
 }
 
-// Sanitize name for terraform style
+// reservedWords are identifiers HCL reserves for block syntax; a resource can't be
+// named exactly one of these. See the TfSanitize doc comment for why sanitized names
+// never actually collide with them.
+var reservedWords = map[string]bool{
+	"count":      true,
+	"for_each":   true,
+	"provider":   true,
+	"providers":  true,
+	"lifecycle":  true,
+	"depends_on": true,
+	"source":     true,
+	"version":    true,
+}
+
+// TfSanitize turns an arbitrary provider-supplied name into a valid Terraform
+// identifier: unsafe characters are hex-escaped and the result is prefixed with
+// "tfer--". That unconditional prefix is also what keeps sanitized names safe from two
+// otherwise-common HCL identifier problems: a name can never start with a digit (the
+// prefix always starts with a letter), and it can never collide with a reserved word
+// like "count", "for_each", or "provider" (those are only invalid as a bare identifier,
+// not as a suffix after "tfer--").
 func TfSanitize(name string) string {
 	name = unsafeChars.ReplaceAllStringFunc(name, escapeRune)
 	name = "tfer--" + name
 	return name
 }
 
+// sortNestedBlockLists makes repeated-block ordering deterministic across re-imports of
+// unchanged infrastructure. A slice whose every element is itself an attribute map
+// (e.g. repeated "ingress" blocks built from an API response in whatever order it
+// happened to return them) is stable-sorted by its canonical JSON encoding, at every
+// nesting depth. Scalar lists are left as-is, since their element order can carry real
+// meaning. Map key order needs no attention here: HclPrint marshals through
+// encoding/json, which already emits object keys sorted.
+func sortNestedBlockLists(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		sorted := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			sorted[k] = sortNestedBlockLists(val)
+		}
+		return sorted
+	case []interface{}:
+		sortedSlice := make([]interface{}, len(v))
+		allMaps := len(v) > 0
+		for i, val := range v {
+			sortedSlice[i] = sortNestedBlockLists(val)
+			if _, ok := sortedSlice[i].(map[string]interface{}); !ok {
+				allMaps = false
+			}
+		}
+		if allMaps {
+			sort.SliceStable(sortedSlice, func(i, j int) bool {
+				bi, _ := json.Marshal(sortedSlice[i])
+				bj, _ := json.Marshal(sortedSlice[j])
+				return string(bi) < string(bj)
+			})
+		}
+		return sortedSlice
+	default:
+		return value
+	}
+}
+
 // Print hcl file from TerraformResource + provider
 func HclPrintResource(resources []Resource, providerData map[string]interface{}) ([]byte, error) {
 	resourcesByType := map[string]map[string]interface{}{}
@@ -219,7 +308,21 @@ func HclPrintResource(resources []Resource, providerData map[string]interface{})
 			return []byte{}, fmt.Errorf("[ERR]: duplicate resource found: %s.%s", res.InstanceInfo.Type, res.ResourceName)
 		}
 
-		r[res.ResourceName] = res.Item
+		item := res.Item
+		if len(res.DependsOn) > 0 || len(res.IgnoreChanges) > 0 {
+			copied := make(map[string]interface{}, len(res.Item)+2)
+			for k, v := range res.Item {
+				copied[k] = v
+			}
+			item = copied
+		}
+		if len(res.DependsOn) > 0 {
+			item["depends_on"] = res.DependsOn
+		}
+		if len(res.IgnoreChanges) > 0 {
+			item["lifecycle"] = map[string]interface{}{"ignore_changes": res.IgnoreChanges}
+		}
+		r[res.ResourceName] = sortNestedBlockLists(item)
 
 		for k := range res.InstanceState.Attributes {
 			if strings.HasSuffix(k, ".%") {
@@ -0,0 +1,93 @@
+package terraform_utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestTfSanitizeAvoidsLeadingDigitsAndReservedWords(t *testing.T) {
+	cases := []string{"123-bucket", "count", "for_each", "provider", "my-name"}
+	for _, name := range cases {
+		sanitized := TfSanitize(name)
+		if sanitized[0] < 'a' || sanitized[0] > 'z' {
+			t.Errorf("expected sanitized name for %q to start with a letter, got %q", name, sanitized)
+		}
+		if reservedWords[sanitized] {
+			t.Errorf("expected sanitized name for %q not to collide with a reserved word, got %q", name, sanitized)
+		}
+	}
+}
+
+func TestHclPrintResourceOrdersRepeatedBlocksDeterministically(t *testing.T) {
+	item := map[string]interface{}{
+		"ingress": []interface{}{
+			map[string]interface{}{"from_port": "443", "to_port": "443"},
+			map[string]interface{}{"from_port": "22", "to_port": "22"},
+		},
+	}
+	resource := Resource{
+		InstanceInfo:  &terraform.InstanceInfo{Type: "aws_security_group"},
+		InstanceState: &terraform.InstanceState{Attributes: map[string]string{}},
+		ResourceName:  "example",
+		Item:          item,
+	}
+	first, err := HclPrintResource([]Resource{resource}, nil)
+	if err != nil {
+		t.Fatalf("HclPrintResource failed: %s", err)
+	}
+
+	// Rebuild the same resource with the ingress blocks in the opposite order, as if a
+	// re-import returned them differently; the rendered HCL should be identical.
+	item["ingress"] = []interface{}{
+		map[string]interface{}{"from_port": "22", "to_port": "22"},
+		map[string]interface{}{"from_port": "443", "to_port": "443"},
+	}
+	resource.Item = item
+	second, err := HclPrintResource([]Resource{resource}, nil)
+	if err != nil {
+		t.Fatalf("HclPrintResource failed: %s", err)
+	}
+
+	if string(first) != string(second) {
+		t.Errorf("expected repeated blocks to render in a deterministic order, got:\n%s\nvs\n%s", first, second)
+	}
+}
+
+func TestHclPrintResourceRendersDependsOnUnquoted(t *testing.T) {
+	resource := Resource{
+		InstanceInfo:  &terraform.InstanceInfo{Type: "aws_iam_role_policy_attachment"},
+		InstanceState: &terraform.InstanceState{Attributes: map[string]string{}},
+		ResourceName:  "example",
+		Item:          map[string]interface{}{"role": "example"},
+		DependsOn:     []string{"aws_iam_role.example"},
+	}
+	out, err := HclPrintResource([]Resource{resource}, nil)
+	if err != nil {
+		t.Fatalf("HclPrintResource failed: %s", err)
+	}
+	if !strings.Contains(string(out), "depends_on = [aws_iam_role.example]") {
+		t.Errorf("expected an unquoted depends_on reference, got:\n%s", out)
+	}
+}
+
+func TestHclPrintResourceRendersIgnoreChanges(t *testing.T) {
+	resource := Resource{
+		InstanceInfo:  &terraform.InstanceInfo{Type: "aws_secretsmanager_secret_version"},
+		InstanceState: &terraform.InstanceState{Attributes: map[string]string{}},
+		ResourceName:  "example",
+		Item:          map[string]interface{}{"secret_id": "example"},
+		IgnoreChanges: []string{"secret_string"},
+	}
+	out, err := HclPrintResource([]Resource{resource}, nil)
+	if err != nil {
+		t.Fatalf("HclPrintResource failed: %s", err)
+	}
+	if !strings.Contains(string(out), "lifecycle {") {
+		t.Errorf("expected a lifecycle block, got:\n%s", out)
+	}
+	if !strings.Contains(string(out), "ignore_changes = [secret_string]") {
+		t.Errorf("expected an unquoted ignore_changes reference, got:\n%s", out)
+	}
+}
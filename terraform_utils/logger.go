@@ -0,0 +1,58 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform_utils
+
+import (
+	"fmt"
+	"log"
+)
+
+// LogLevel gates which severities Logf actually writes, so a quiet production run isn't
+// drowned in per-resource debug noise but a caller can turn it back on with -v.
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// logLevel is the process-wide verbosity, set once by the CLI before Import runs.
+var logLevel = LogLevelWarn
+
+// SetLogLevel controls which severities Logf writes. Generators and terraform_utils
+// itself always call Logf at the severity that matches the message; it's the caller's
+// verbosity setting, not the call site, that decides what's actually printed.
+func SetLogLevel(level LogLevel) {
+	logLevel = level
+}
+
+// Logf writes a leveled log line through the standard logger if level is at or above the
+// level set with SetLogLevel, prefixed with its severity so -v output can be grepped by
+// level.
+func Logf(level LogLevel, format string, args ...interface{}) {
+	if level < logLevel {
+		return
+	}
+	log.Printf("[%s] %s", levelPrefixes[level], fmt.Sprintf(format, args...))
+}
+
+var levelPrefixes = map[LogLevel]string{
+	LogLevelDebug: "DEBUG",
+	LogLevelInfo:  "INFO",
+	LogLevelWarn:  "WARN",
+	LogLevelError: "ERROR",
+}
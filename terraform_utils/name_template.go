@@ -0,0 +1,64 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform_utils
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var nameTemplatePlaceholder = regexp.MustCompile(`\{([a-zA-Z0-9_.]+)\}`)
+
+// ApplyNameTemplate rewrites each resource's ResourceName from a user-supplied
+// template, e.g. "{type}-{Name}". It must run right after InitResources, before
+// RefreshResources/ConvertTFstate populate Item, so the only attributes it can
+// reference are the ones a generator already set: InstanceState.Attributes and
+// AdditionalFields. "{id}" and "{type}" are special-cased to InstanceState.ID and
+// InstanceInfo.Type, since those are always present regardless of attributes. If a
+// placeholder doesn't resolve for a given
+// resource, that resource's generator-assigned name is left untouched rather than
+// baking a literal "{missing}" into the config, so a template written for one resource
+// type doesn't corrupt the naming of others in the same service.
+func ApplyNameTemplate(resources []Resource, tmpl string) {
+	if tmpl == "" {
+		return
+	}
+	for i := range resources {
+		r := &resources[i]
+		missing := false
+		rendered := nameTemplatePlaceholder.ReplaceAllStringFunc(tmpl, func(match string) string {
+			attr := match[1 : len(match)-1]
+			switch attr {
+			case "id":
+				return r.InstanceState.ID
+			case "type":
+				return r.InstanceInfo.Type
+			}
+			if value, ok := r.InstanceState.Attributes[attr]; ok {
+				return value
+			}
+			if value, ok := r.AdditionalFields[attr]; ok {
+				return fmt.Sprintf("%v", value)
+			}
+			missing = true
+			return match
+		})
+		if missing {
+			continue
+		}
+		r.ResourceName = TfSanitize(rendered)
+		r.InstanceInfo.Id = fmt.Sprintf("%s.%s", r.InstanceInfo.Type, r.ResourceName)
+	}
+}
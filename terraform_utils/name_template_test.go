@@ -0,0 +1,61 @@
+package terraform_utils
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestApplyNameTemplateRendersPlaceholders(t *testing.T) {
+	resources := []Resource{
+		{
+			ResourceName:     "myBucket",
+			InstanceInfo:     &terraform.InstanceInfo{Type: "aws_s3_bucket", Id: "aws_s3_bucket.myBucket"},
+			InstanceState:    &terraform.InstanceState{ID: "mybucket1", Attributes: map[string]string{"region": "us_east_1"}},
+			AdditionalFields: map[string]interface{}{},
+		},
+	}
+
+	ApplyNameTemplate(resources, "{region}_{id}")
+
+	want := TfSanitize("us_east_1_mybucket1")
+	if resources[0].ResourceName != want {
+		t.Errorf("expected rendered name %v, got %v", want, resources[0].ResourceName)
+	}
+	if resources[0].InstanceInfo.Id != "aws_s3_bucket."+want {
+		t.Errorf("expected InstanceInfo.Id to track the rendered name, got %v", resources[0].InstanceInfo.Id)
+	}
+}
+
+func TestApplyNameTemplateLeavesResourceUntouchedWhenAttributeMissing(t *testing.T) {
+	resources := []Resource{
+		{
+			ResourceName:     "myBucket",
+			InstanceInfo:     &terraform.InstanceInfo{Type: "aws_s3_bucket", Id: "aws_s3_bucket.myBucket"},
+			InstanceState:    &terraform.InstanceState{ID: "mybucket-1", Attributes: map[string]string{}},
+			AdditionalFields: map[string]interface{}{},
+		},
+	}
+
+	ApplyNameTemplate(resources, "{not_set}")
+
+	if resources[0].ResourceName != "myBucket" {
+		t.Errorf("expected original name to be kept, got %v", resources[0].ResourceName)
+	}
+}
+
+func TestApplyNameTemplateNoopsWhenEmpty(t *testing.T) {
+	resources := []Resource{
+		{
+			ResourceName:  "myBucket",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_s3_bucket", Id: "aws_s3_bucket.myBucket"},
+			InstanceState: &terraform.InstanceState{ID: "mybucket-1"},
+		},
+	}
+
+	ApplyNameTemplate(resources, "")
+
+	if resources[0].ResourceName != "myBucket" {
+		t.Errorf("expected name to be unchanged, got %v", resources[0].ResourceName)
+	}
+}
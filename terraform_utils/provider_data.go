@@ -0,0 +1,60 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform_utils
+
+import "fmt"
+
+// SecretVarRef returns the interpolation syntax for referencing a Terraform variable
+// named varName, for a provider's GetProviderData to use as a config value it wants to
+// keep out of the generated provider.tf (an API token, key, or password) instead of
+// baking in the literal value. Pair it with SecretVariables so the variable resolves.
+func SecretVarRef(varName string) string {
+	return fmt.Sprintf("${var.%s}", varName)
+}
+
+// SecretVariables returns a "variable" block declaring an empty variable for each name
+// in secretVars, merged into a GetProviderData result alongside ProviderData so a
+// config value set with SecretVarRef has somewhere to resolve from (a .tfvars file or
+// TF_VAR_ environment variable, supplied by whoever runs `terraform apply`).
+func SecretVariables(secretVars ...string) map[string]interface{} {
+	if len(secretVars) == 0 {
+		return map[string]interface{}{}
+	}
+	variables := map[string]interface{}{}
+	for _, name := range secretVars {
+		variables[name] = map[string]interface{}{}
+	}
+	return map[string]interface{}{"variable": variables}
+}
+
+// ProviderData assembles the map a provider's GetProviderData returns: the "provider"
+// block itself, plus a matching "terraform.required_providers" entry pinned to
+// version, so `terraform init` resolves the same provider build used for refresh
+// instead of whatever happens to be newest.
+func ProviderData(providerName, version string, config map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{
+		"provider": map[string]interface{}{
+			providerName: config,
+		},
+	}
+	if version != "" {
+		data["terraform"] = map[string]interface{}{
+			"required_providers": map[string]interface{}{
+				providerName: version,
+			},
+		}
+	}
+	return data
+}
@@ -15,6 +15,7 @@
 package provider_wrapper
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -23,17 +24,20 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/zclconf/go-cty/cty/gocty"
 
 	"github.com/zclconf/go-cty/cty"
 
+	hcversion "github.com/hashicorp/go-version"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/terraform/configs/configschema"
 	tfplugin "github.com/hashicorp/terraform/plugin"
 	"github.com/hashicorp/terraform/providers"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform/tfdiags"
 	"github.com/hashicorp/terraform/version"
 )
 
@@ -54,6 +58,17 @@ type ProviderWrapper struct {
 	rpcClient    plugin.ClientProtocol
 	providerName string
 	config       cty.Value
+
+	schemaMutex sync.Mutex
+	schema      *providers.GetSchemaResponse
+
+	impliedTypeMutex sync.Mutex
+	impliedTypes     map[string]cty.Type
+
+	// versionConstraint, if set, restricts getProviderFileName to a plugin binary
+	// whose encoded version satisfies it, so schema drift between provider releases
+	// doesn't silently change which attributes come back from one run to the next.
+	versionConstraint string
 }
 
 func NewProviderWrapper(providerName string, providerConfig cty.Value) (*ProviderWrapper, error) {
@@ -64,12 +79,46 @@ func NewProviderWrapper(providerName string, providerConfig cty.Value) (*Provide
 	return p, err
 }
 
+// NewProviderWrapperWithVersion is like NewProviderWrapper but only launches a
+// plugin binary matching versionConstraint (e.g. "~> 2.70"), failing clearly if no
+// installed plugin satisfies it instead of silently using whatever is newest.
+func NewProviderWrapperWithVersion(providerName string, providerConfig cty.Value, versionConstraint string) (*ProviderWrapper, error) {
+	p := &ProviderWrapper{}
+	p.providerName = providerName
+	p.config = providerConfig
+	p.versionConstraint = versionConstraint
+	err := p.initProvider()
+	return p, err
+}
+
+// GetSchema returns the provider's schema, decoding it from the plugin at most once
+// per ProviderWrapper and caching the result for reuse by GetReadOnlyAttributes,
+// GetRequiredAttributes, and Refresh. GetSchema on large providers (AWS) is expensive
+// and memory-heavy, so this matters once a run refreshes hundreds of resources of a
+// handful of types. Safe for concurrent use from the parallel-refresh path; the cache
+// is per-wrapper-instance, so separate provider processes stay isolated.
+func (p *ProviderWrapper) GetSchema() providers.GetSchemaResponse {
+	p.schemaMutex.Lock()
+	defer p.schemaMutex.Unlock()
+	if p.schema == nil {
+		schema := p.Provider.GetSchema()
+		p.schema = &schema
+	}
+	return *p.schema
+}
+
 func (p *ProviderWrapper) Kill() {
 	p.client.Kill()
 }
 
+// GetReadOnlyAttributes returns, for each of the given resource types, regex patterns
+// matching the schema's computed-only attributes (Computed && !Optional && !Required) --
+// values the provider fills in itself and won't accept back as input, such as an
+// aws_instance's arn. IgnoreKeys uses these patterns to prune such attributes from
+// InstanceState before conversion, so generators don't each need to maintain their own
+// hand-picked computed-field list.
 func (p *ProviderWrapper) GetReadOnlyAttributes(resourceTypes []string) (map[string][]string, error) {
-	r := p.Provider.GetSchema()
+	r := p.GetSchema()
 
 	if r.Diagnostics.HasErrors() {
 		return nil, r.Diagnostics.Err()
@@ -79,7 +128,7 @@ func (p *ProviderWrapper) GetReadOnlyAttributes(resourceTypes []string) (map[str
 		if contains(resourceTypes, resourceName) {
 			readOnlyAttributes[resourceName] = append(readOnlyAttributes[resourceName], "^id$")
 			for k, v := range obj.Block.Attributes {
-				if !v.Optional && !v.Required {
+				if v.Computed && !v.Optional && !v.Required {
 					if v.Type.IsListType() || v.Type.IsSetType() {
 						readOnlyAttributes[resourceName] = append(readOnlyAttributes[resourceName], "^"+k+".(.*)")
 					} else {
@@ -94,6 +143,31 @@ func (p *ProviderWrapper) GetReadOnlyAttributes(resourceTypes []string) (map[str
 	return readOnlyAttributes, nil
 }
 
+// GetRequiredAttributes returns, for each of the given resource types, the top-level
+// attribute names the schema marks Required. Callers use this to flag a Resource
+// whose converted Item is missing one of these, which usually means an
+// AllowEmptyValues/IgnoreKeys pattern over-matched and pruned a value the provider
+// needs, turning what would be a cryptic `terraform plan` failure into an actionable
+// report at import time.
+func (p *ProviderWrapper) GetRequiredAttributes(resourceTypes []string) (map[string][]string, error) {
+	r := p.GetSchema()
+	if r.Diagnostics.HasErrors() {
+		return nil, r.Diagnostics.Err()
+	}
+	requiredAttributes := map[string][]string{}
+	for resourceName, obj := range r.ResourceTypes {
+		if !contains(resourceTypes, resourceName) {
+			continue
+		}
+		for k, v := range obj.Block.Attributes {
+			if v.Required {
+				requiredAttributes[resourceName] = append(requiredAttributes[resourceName], k)
+			}
+		}
+	}
+	return requiredAttributes, nil
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
@@ -110,7 +184,7 @@ func (p *ProviderWrapper) readObjBlocks(block map[string]*configschema.NestedBlo
 		}
 		fieldCount := 0
 		for key, l := range v.Attributes {
-			if !l.Optional && !l.Required {
+			if l.Computed && !l.Optional && !l.Required {
 				fieldCount++
 				switch v.Nesting {
 				case configschema.NestingList:
@@ -139,9 +213,73 @@ func (p *ProviderWrapper) readObjBlocks(block map[string]*configschema.NestedBlo
 	return readOnlyAttributes
 }
 
-func (p *ProviderWrapper) Refresh(info *terraform.InstanceInfo, state *terraform.InstanceState) (*terraform.InstanceState, error) {
-	schema := p.Provider.GetSchema()
-	impliedType := schema.ResourceTypes[info.Type].Block.ImpliedType()
+// RefreshDiagnosticError preserves a failed ReadResource call's severity and
+// summary/detail instead of collapsing every diagnostic into one generic error
+// string. Error() carries Summary and Detail unchanged, so Resource.Refresh's retry
+// logic, which matches the error text, can tell a retryable throttling response
+// ("rate limit"/"throttl") from a permanent failure like "access denied".
+type RefreshDiagnosticError struct {
+	Severity tfdiags.Severity
+	Summary  string
+	Detail   string
+}
+
+func (e *RefreshDiagnosticError) Error() string {
+	if e.Detail == "" {
+		return e.Summary
+	}
+	return fmt.Sprintf("%s: %s", e.Summary, e.Detail)
+}
+
+// newRefreshDiagnosticError picks the first error-severity diagnostic (if any) and
+// wraps it as a RefreshDiagnosticError, falling back to diags.Err() for the rare case
+// of only warning-severity diagnostics reaching here.
+func newRefreshDiagnosticError(diags tfdiags.Diagnostics) error {
+	for _, diag := range diags {
+		if diag.Severity() == tfdiags.Error {
+			desc := diag.Description()
+			return &RefreshDiagnosticError{Severity: diag.Severity(), Summary: desc.Summary, Detail: desc.Detail}
+		}
+	}
+	return diags.Err()
+}
+
+// impliedType returns the cty.Type implied by resourceType's schema block, computing
+// it at most once per type and reusing it across every Resource.Refresh call for that
+// type. ImpliedType walks the whole (possibly deeply nested) block schema, so this
+// matters the same way the schema cache does once a run refreshes hundreds of
+// same-type resources.
+//
+// This is as close to "batching" as the vendored plugin protocol allows: the
+// providers.Interface this repo targets (github.com/hashicorp/terraform/providers,
+// the pre-1.0 gRPC provider protocol) only exposes a per-resource ReadResource RPC --
+// there is no multi-instance read call to group same-type resources onto, so the
+// per-resource RPC count is unavoidable here. Concurrency across resources is instead
+// handled by RefreshResourceWorker's worker pool in terraform_utils/utils.go.
+func (p *ProviderWrapper) impliedType(resourceType string) cty.Type {
+	p.impliedTypeMutex.Lock()
+	defer p.impliedTypeMutex.Unlock()
+	if p.impliedTypes == nil {
+		p.impliedTypes = map[string]cty.Type{}
+	}
+	if t, ok := p.impliedTypes[resourceType]; ok {
+		return t
+	}
+	t := p.GetSchema().ResourceTypes[resourceType].Block.ImpliedType()
+	p.impliedTypes[resourceType] = t
+	return t
+}
+
+// Refresh reads info/state's current value from the provider. ctx is checked before
+// the RPC is issued, so a cancellation (e.g. Ctrl-C) skips resources that haven't
+// started yet; it can't interrupt a ReadResource call already in flight, since
+// providers.Interface.ReadResource (the vendored pre-1.0 provider protocol this repo
+// targets) takes no context and blocks for the RPC's full duration.
+func (p *ProviderWrapper) Refresh(ctx context.Context, info *terraform.InstanceInfo, state *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	impliedType := p.impliedType(info.Type)
 	priorState, err := state.AttrsAsObjectValue(impliedType)
 	if err != nil {
 		return nil, err
@@ -161,7 +299,7 @@ func (p *ProviderWrapper) Refresh(info *terraform.InstanceInfo, state *terraform
 			Private:    []byte{},
 		})
 		if resp.Diagnostics.HasErrors() {
-			return nil, resp.Diagnostics.Err()
+			return nil, newRefreshDiagnosticError(resp.Diagnostics)
 		}
 	}
 
@@ -170,11 +308,14 @@ func (p *ProviderWrapper) Refresh(info *terraform.InstanceInfo, state *terraform
 		return nil, errors.New(msg)
 	}
 
-	return terraform.NewInstanceStateShimmedFromValue(resp.NewState, int(schema.Provider.Version)), nil
+	// schema_version must match this resource type's own schema, not the provider's
+	// top-level config schema: a mismatch is what makes Terraform run a state upgrade
+	// on first use, sometimes mutating values in surprising ways.
+	return terraform.NewInstanceStateShimmedFromValue(resp.NewState, int(p.GetSchema().ResourceTypes[info.Type].Version)), nil
 }
 
 func (p *ProviderWrapper) initProvider() error {
-	providerFilePath, err := getProviderFileName(p.providerName)
+	providerFilePath, err := getProviderFileName(p.providerName, p.versionConstraint)
 	if err != nil {
 		return err
 	}
@@ -204,7 +345,7 @@ func (p *ProviderWrapper) initProvider() error {
 
 	p.Provider = raw.(*tfplugin.GRPCProvider)
 
-	config, err := p.Provider.GetSchema().Provider.Block.CoerceValue(p.config)
+	config, err := p.GetSchema().Provider.Block.CoerceValue(p.config)
 	if err != nil {
 		return err
 	}
@@ -216,7 +357,11 @@ func (p *ProviderWrapper) initProvider() error {
 	return nil
 }
 
-func getProviderFileName(providerName string) (string, error) {
+// getProviderFileName locates the terraform-provider-<providerName> plugin binary in
+// the usual plugin directories. If versionConstraint is non-empty, only a binary whose
+// filename encodes a version satisfying it is returned, and a non-matching directory
+// is reported as an error rather than silently falling back to whatever is newest.
+func getProviderFileName(providerName, versionConstraint string) (string, error) {
 	defaultDataDir := os.Getenv("TF_DATA_DIR")
 	if defaultDataDir == "" {
 		defaultDataDir = DefaultDataDir
@@ -230,20 +375,48 @@ func getProviderFileName(providerName string) (string, error) {
 			return "", err
 		}
 	}
+	var constraints hcversion.Constraints
+	if versionConstraint != "" {
+		constraints, err = hcversion.NewConstraint(versionConstraint)
+		if err != nil {
+			return "", fmt.Errorf("invalid provider version constraint %q: %s", versionConstraint, err)
+		}
+	}
 	providerFilePath := ""
 	for _, file := range files {
 		if file.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(file.Name(), "terraform-provider-"+providerName) {
-			providerFilePath = pluginPath + string(os.PathSeparator) + file.Name()
+		if !strings.HasPrefix(file.Name(), "terraform-provider-"+providerName) {
+			continue
+		}
+		if constraints != nil {
+			fileVersion, err := parseProviderFileVersion(file.Name())
+			if err != nil || !constraints.Check(fileVersion) {
+				continue
+			}
 		}
+		providerFilePath = pluginPath + string(os.PathSeparator) + file.Name()
+	}
+	if providerFilePath == "" && versionConstraint != "" {
+		return "", fmt.Errorf("no terraform-provider-%s plugin matching version constraint %q found in %s", providerName, versionConstraint, pluginPath)
 	}
 	return providerFilePath, nil
 }
 
+// parseProviderFileVersion extracts the version segment (following
+// https://www.terraform.io/docs/configuration/providers.html#plugin-names-and-versions)
+// from a plugin file name such as terraform-provider-aws_v2.70.0_x4.
+func parseProviderFileVersion(fileName string) (*hcversion.Version, error) {
+	parts := strings.Split(fileName, "_")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("provider file name %q doesn't encode a version", fileName)
+	}
+	return hcversion.NewVersion(strings.TrimPrefix(parts[1], "v"))
+}
+
 func GetProviderVersion(providerName string) string {
-	providerFilePath, err := getProviderFileName(providerName)
+	providerFilePath, err := getProviderFileName(providerName, "")
 	if err != nil {
 		log.Println("Can't find provider file path. Ensure that you are following https://www.terraform.io/docs/configuration/providers.html#third-party-plugins.")
 		return ""
@@ -15,13 +15,17 @@
 package terraform_utils
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils/provider_wrapper"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
 )
 
 type Resource struct {
@@ -34,17 +38,355 @@ type Resource struct {
 	IgnoreKeys       []string               `json:",omitempty"`
 	AllowEmptyValues []string               `json:",omitempty"`
 	AdditionalFields map[string]interface{} `json:",omitempty"`
+	// RequestedOutputs lists extra root outputs a generator wants materialized into
+	// outputs.tf alongside the automatic per-resource ID output, e.g. so a user who
+	// imports a VPC can reference its id from other modules without hand-authoring an
+	// output block. Populate it via AddOutput.
+	RequestedOutputs []RequestedOutput `json:",omitempty"`
+	// DependsOn lists other resources' addresses (e.g. "aws_iam_role.foo") that must
+	// be applied before this one. Generators populate this during PostConvertHook for
+	// orderings they know about that aren't otherwise expressible via an attribute
+	// reference, e.g. an IAM policy attachment that must exist after the role it
+	// attaches to. The output stage renders these as a depends_on block.
+	DependsOn []string `json:",omitempty"`
+	// SensitiveAttrs lists dotted paths (GetStateAttr syntax) into Item that hold
+	// secrets an API happened to return in plaintext (passwords, private keys,
+	// tokens). RedactSensitiveAttrs overwrites them with SensitiveValuePlaceholder
+	// before the resource is written out, so they don't land in version control by
+	// default. Populate it via MarkSensitive rather than appending directly.
+	SensitiveAttrs []string `json:",omitempty"`
+
+	// SlowQueryRequired is a convenience flag for a generator that hits a
+	// heavily-throttled API: setting it makes Refresh sleep DefaultSlowQueryDelay
+	// before issuing the provider RPC. For finer control, set SlowQueryDelay directly.
+	SlowQueryRequired bool `json:",omitempty"`
+	// SlowQueryDelay overrides SlowQueryRequired's default sleep duration for this
+	// resource specifically. Zero means "use SlowQueryRequired's default, if set".
+	SlowQueryDelay time.Duration `json:",omitempty"`
+
+	// IDKeyPriority lists, in preference order, the attributes GetIDKey should look for
+	// in InstanceState.Attributes before falling back to DefaultIDKeyPriority. Set this
+	// when a provider's resources are best referenced by something other than
+	// self_link/id, e.g. AWS generators preferring "arn" for import stability.
+	IDKeyPriority []string `json:",omitempty"`
+	// ImportIDOverride, if set, is used in place of InstanceState.ID as the ID half of
+	// `terraform import <address> <id>` for this resource. Set it during PostConvertHook
+	// when the provider's import format is a composite of several attributes (e.g.
+	// "<cluster_id>/<node_id>") rather than the plain ID Refresh imported with.
+	ImportIDOverride string `json:",omitempty"`
+	// IgnoreChanges lists top-level attribute names the output stage renders into a
+	// `lifecycle { ignore_changes = [...] }` block, so a `plan` right after import stays
+	// clean for attributes known to drift on their own (auto-rotated secrets,
+	// server-assigned timestamps). Generators can default this for known-volatile
+	// attributes during PostConvertHook via AddIgnoreChanges; a user-facing flag can
+	// augment it the same way a filter augments IgnoreKeys.
+	IgnoreChanges []string `json:",omitempty"`
+	// DataFiles holds binary assets a generator fetched alongside a resource's
+	// attributes (a Lambda deployment package, a TLS certificate, a policy document)
+	// that belong on disk next to the generated config rather than inlined into an
+	// attribute value. Keyed by a generator-chosen name (e.g. "zip", "cert.pem").
+	// Populate it via AddDataFile; terraform_output.WriteDataFiles writes these out,
+	// and the import command runs it for every service directory it writes, whatever
+	// the state output mode.
+	DataFiles map[string]DataFile `json:",omitempty"`
+	// ImportIDFormatter, if set, transforms FormattedImportID's result into the string
+	// `terraform import`/an import block actually expects, for providers whose import
+	// format is a composite of several attributes (e.g. "<zone>/<record>") rather than
+	// the plain ImportID used internally for refresh and filtering. Set it during
+	// PostConvertHook. A resource without one uses the raw ImportID, preserving
+	// current behavior.
+	ImportIDFormatter ImportIDFormatter `json:"-"`
+}
+
+// ImportIDFormatter transforms a resource's internal ImportID into the string a
+// `terraform import` command or import block expects.
+type ImportIDFormatter func(id string) string
+
+// DataFile is one binary asset in Resource.DataFiles.
+type DataFile struct {
+	Content []byte
+	// Compress gzips Content on write (see terraform_output.WriteDataFiles), for
+	// assets large enough that they'd otherwise dominate the output directory's size.
+	// The written file name gets a ".gz" suffix so a generator's own
+	// DataFilePath-derived attribute value stays correct either way.
+	Compress bool
+}
+
+// AddDataFile records a binary asset to be written alongside this resource's config by
+// terraform_output.WriteDataFiles, keyed by name (e.g. "zip" for a Lambda package,
+// "cert.pem" for a certificate).
+func (r *Resource) AddDataFile(name string, content []byte, compress bool) {
+	if r.DataFiles == nil {
+		r.DataFiles = map[string]DataFile{}
+	}
+	r.DataFiles[name] = DataFile{Content: content, Compress: compress}
+}
+
+// DataFilePath returns the path, relative to the service's output directory, that
+// terraform_output.WriteDataFiles writes name's content to: "data/<resource
+// name>/<name>", with a ".gz" suffix if it was added with compress=true. A generator
+// sets this as the attribute value that should point at the written file, e.g.
+// `filename = "${path.module}/" + r.DataFilePath("zip")`.
+func (r *Resource) DataFilePath(name string) string {
+	path := fmt.Sprintf("data/%s/%s", r.ResourceName, name)
+	if df, ok := r.DataFiles[name]; ok && df.Compress {
+		path += ".gz"
+	}
+	return path
+}
+
+// Address is this resource's Terraform address, e.g. "aws_instance.example".
+func (r Resource) Address() string {
+	return r.InstanceInfo.Type + "." + r.ResourceName
+}
+
+// ImportID is the ID half of `terraform import <address> <id>` for this resource:
+// ImportIDOverride if a generator set one for a composite import format, otherwise the
+// plain ID Refresh imported the resource with.
+func (r Resource) ImportID() string {
+	if r.ImportIDOverride != "" {
+		return r.ImportIDOverride
+	}
+	return r.InstanceState.ID
+}
+
+// FormattedImportID is ImportID run through ImportIDFormatter, if the generator set
+// one, for output stages (import.sh, import blocks) that need the string `terraform
+// import` itself expects rather than the internal ID used for refresh and filtering.
+// A resource without a formatter gets the raw ImportID back, unchanged.
+func (r Resource) FormattedImportID() string {
+	if r.ImportIDFormatter == nil {
+		return r.ImportID()
+	}
+	return r.ImportIDFormatter(r.ImportID())
+}
+
+// DefaultIDKeyPriority is the attribute preference order GetIDKey falls back to when a
+// Resource doesn't set IDKeyPriority itself.
+var DefaultIDKeyPriority = []string{"self_link", "id"}
+
+// DefaultSlowQueryDelay is the sleep duration SlowQueryRequired maps to. A user
+// importing from a throttled API can tune this globally without editing generators.
+var DefaultSlowQueryDelay = 200 * time.Millisecond
+
+// slowQueryDelay resolves the delay Refresh should sleep before the provider RPC.
+func (r *Resource) slowQueryDelay() time.Duration {
+	if r.SlowQueryDelay > 0 {
+		return r.SlowQueryDelay
+	}
+	if r.SlowQueryRequired {
+		return DefaultSlowQueryDelay
+	}
+	return 0
 }
 
 type ApplicableFilter interface {
 	IsApplicable(resourceName string) bool
 }
 
+const regexValuePrefix = "~"
+
 type ResourceFilter struct {
 	ApplicableFilter
 	ResourceName     string
 	FieldPath        string
 	AcceptableValues []string
+	Negate           bool
+	valueRegexps     []*regexp.Regexp
+	comparisons      []*comparison
+}
+
+// compileValueRegexps compiles the regexes for every AcceptableValues entry prefixed with
+// "~", so Filter can reuse them for every resource instead of recompiling per call.
+// Entries that aren't regexes get a nil slot at the same index.
+func (rf *ResourceFilter) compileValueRegexps() error {
+	var regexps []*regexp.Regexp
+	for i, acceptableValue := range rf.AcceptableValues {
+		if !strings.HasPrefix(acceptableValue, regexValuePrefix) {
+			continue
+		}
+		if regexps == nil {
+			regexps = make([]*regexp.Regexp, len(rf.AcceptableValues))
+		}
+		pattern := strings.TrimPrefix(acceptableValue, regexValuePrefix)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regex filter value %q: %s", acceptableValue, err)
+		}
+		regexps[i] = re
+	}
+	rf.valueRegexps = regexps
+	return nil
+}
+
+// comparisonOperators must be checked longest-prefix-first so ">=" isn't mistaken for ">".
+var comparisonOperators = []string{">=", "<=", ">", "<"}
+
+type comparison struct {
+	operator  string
+	threshold float64
+}
+
+// compileComparisons parses the AcceptableValues entries that start with a comparison
+// operator (>, >=, <, <=) into a numeric threshold, once per filter.
+func (rf *ResourceFilter) compileComparisons() error {
+	var comparisons []*comparison
+	for i, acceptableValue := range rf.AcceptableValues {
+		operator := ""
+		for _, candidate := range comparisonOperators {
+			if strings.HasPrefix(acceptableValue, candidate) {
+				operator = candidate
+				break
+			}
+		}
+		if operator == "" {
+			continue
+		}
+		var threshold float64
+		numberVal, err := cty.ParseNumberVal(strings.TrimPrefix(acceptableValue, operator))
+		if err == nil {
+			err = gocty.FromCtyValue(numberVal, &threshold)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid numeric filter value %q: %s", acceptableValue, err)
+		}
+		if comparisons == nil {
+			comparisons = make([]*comparison, len(rf.AcceptableValues))
+		}
+		comparisons[i] = &comparison{operator: operator, threshold: threshold}
+	}
+	rf.comparisons = comparisons
+	return nil
+}
+
+// matches evaluates a comparison against a resource's field value, which must itself be
+// numeric; string fields are reported and treated as non-matching rather than panicking.
+func (c *comparison) matches(val interface{}) bool {
+	numberVal, err := cty.ParseNumberVal(fmt.Sprintf("%v", val))
+	var actual float64
+	if err == nil {
+		err = gocty.FromCtyValue(numberVal, &actual)
+	}
+	if err != nil {
+		Logf(LogLevelDebug, "cannot apply comparison filter %s to non-numeric value %v: %s", c.operator, val, err)
+		return false
+	}
+	switch c.operator {
+	case ">":
+		return actual > c.threshold
+	case ">=":
+		return actual >= c.threshold
+	case "<":
+		return actual < c.threshold
+	case "<=":
+		return actual <= c.threshold
+	default:
+		return false
+	}
+}
+
+// valueToString renders a filtered attribute value the way a user would type it on the
+// command line, so it can be compared against AcceptableValues or matched against a
+// regex. Plain strings pass through unchanged. cty.Value falls through fmt.Sprintf's
+// GoString by default, which renders a bool as "cty.True" and a list as a Go literal;
+// both are handled explicitly here instead so booleans and collections of primitives
+// stay matchable.
+func valueToString(val interface{}) string {
+	ctyVal, ok := val.(cty.Value)
+	if !ok {
+		return fmt.Sprintf("%v", val)
+	}
+	if ctyVal.IsNull() || !ctyVal.IsKnown() {
+		return ""
+	}
+	ty := ctyVal.Type()
+	switch {
+	case ty == cty.String:
+		return ctyVal.AsString()
+	case ty == cty.Bool:
+		return strconv.FormatBool(ctyVal.True())
+	case ty == cty.Number:
+		return ctyVal.AsBigFloat().Text('f', -1)
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		var parts []string
+		for it := ctyVal.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			parts = append(parts, valueToString(elem))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// nameFieldPath is a FieldPath sentinel matching against the resource's generated
+// Terraform name (Resource.ResourceName, the second half of Address), rather than a
+// state attribute or "id". Useful after a first import reveals the sanitized names and
+// the user wants to re-run against just a few of them by name instead of by ID.
+const nameFieldPath = "__name__"
+
+// tagFilterType is the Type= sentinel that marks a "Type=tag;Name=<key>;Value=<value>"
+// clause as a tag filter, rather than restricting the filter to a resource type
+// literally named "tag".
+const tagFilterType = "tag"
+
+// tagFieldPathPrefix marks a ResourceFilter.FieldPath as a TagFilter: the key to look up
+// is everything after the prefix, rather than a literal attribute path.
+const tagFieldPathPrefix = "tag:"
+
+// tagAttributeNames are the top-level attributes providers commonly store tags under,
+// tried in order: AWS and Azure model them as a map under "tags", GCP under "labels",
+// and a handful of older AWS resources (e.g. aws_autoscaling_group) as a list of
+// {key, value} objects under "tag".
+var tagAttributeNames = []string{"tags", "labels", "tag"}
+
+// tagValues resolves a tag key against whichever of the common tag shapes the resource
+// actually uses, so a single "Type=tag;Name=<key>;Value=<value>" filter works the same
+// way across AWS, GCP, and Azure resources without the user needing to know the shape.
+func tagValues(tagKey string, resource Resource) []interface{} {
+	for _, attr := range tagAttributeNames {
+		path := attr + "." + tagKey
+		if vals := WalkAndGet(path, resource.Item); len(vals) > 0 {
+			return vals
+		}
+		if vals := WalkAndGet(path, resource.InstanceState.Attributes); len(vals) > 0 {
+			return vals
+		}
+		if vals := tagListValues(attr, tagKey, resource.Item); len(vals) > 0 {
+			return vals
+		}
+	}
+	return nil
+}
+
+// tagListValues handles the {key, value} (or {Key, Value}) object list shape, which
+// WalkAndGet's dotted-path syntax can't express since the match key is a sibling field
+// rather than the map key itself.
+func tagListValues(attr, tagKey string, item map[string]interface{}) []interface{} {
+	list, ok := item[attr].([]interface{})
+	if !ok {
+		return nil
+	}
+	var vals []interface{}
+	for _, entry := range list {
+		obj, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, hasKey := obj["key"]
+		if !hasKey {
+			key, hasKey = obj["Key"]
+		}
+		if !hasKey || fmt.Sprintf("%v", key) != tagKey {
+			continue
+		}
+		if value, hasValue := obj["value"]; hasValue {
+			vals = append(vals, value)
+		} else if value, hasValue := obj["Value"]; hasValue {
+			vals = append(vals, value)
+		}
+	}
+	return vals
 }
 
 func (rf *ResourceFilter) Filter(resource Resource) bool {
@@ -53,21 +395,41 @@ func (rf *ResourceFilter) Filter(resource Resource) bool {
 	}
 	var vals []interface{}
 	if rf.FieldPath == "id" {
-		vals = []interface{}{resource.InstanceState.ID}
+		vals = []interface{}{resource.ImportID()}
+	} else if rf.FieldPath == nameFieldPath {
+		vals = []interface{}{resource.ResourceName}
+	} else if strings.HasPrefix(rf.FieldPath, tagFieldPathPrefix) {
+		vals = tagValues(strings.TrimPrefix(rf.FieldPath, tagFieldPathPrefix), resource)
 	} else {
 		vals = WalkAndGet(rf.FieldPath, resource.InstanceState.Attributes)
 		if len(vals) == 0 {
 			vals = WalkAndGet(rf.FieldPath, resource.Item)
 		}
 	}
+	matched := false
 	for _, val := range vals {
-		for _, acceptableValue := range rf.AcceptableValues {
-			if val == acceptableValue {
-				return true
+		for i, acceptableValue := range rf.AcceptableValues {
+			if i < len(rf.comparisons) && rf.comparisons[i] != nil {
+				if rf.comparisons[i].matches(val) {
+					matched = true
+				}
+				continue
+			}
+			if i < len(rf.valueRegexps) && rf.valueRegexps[i] != nil {
+				if rf.valueRegexps[i].MatchString(valueToString(val)) {
+					matched = true
+				}
+				continue
+			}
+			if valueToString(val) == acceptableValue {
+				matched = true
 			}
 		}
 	}
-	return false
+	if rf.Negate {
+		return !matched
+	}
+	return matched
 }
 
 func (rf *ResourceFilter) IsApplicable(resourceName string) bool {
@@ -75,7 +437,53 @@ func (rf *ResourceFilter) IsApplicable(resourceName string) bool {
 }
 
 func (rf *ResourceFilter) isInitial() bool {
-	return rf.FieldPath == "id"
+	return rf.FieldPath == "id" || rf.FieldPath == nameFieldPath
+}
+
+type FilterLogic string
+
+const (
+	FilterLogicAnd FilterLogic = "AND"
+	FilterLogicOr  FilterLogic = "OR"
+)
+
+// ResourceFilterGroup combines several ResourceFilter into a single predicate, either
+// requiring all of them to match (AND, the default) or any one of them (OR). A single
+// --filter flag parses into an AND-group of one, so existing behavior is unchanged;
+// "||" between filter clauses produces an OR-group.
+type ResourceFilterGroup struct {
+	Filters []ResourceFilter
+	Logic   FilterLogic
+}
+
+func (fg *ResourceFilterGroup) Filter(resource Resource) bool {
+	if fg.Logic == FilterLogicOr {
+		for i := range fg.Filters {
+			if fg.Filters[i].Filter(resource) {
+				return true
+			}
+		}
+		return false
+	}
+	for i := range fg.Filters {
+		if !fg.Filters[i].Filter(resource) {
+			return false
+		}
+	}
+	return true
+}
+
+// isInitial reports whether every filter in the group can run before refresh (id- or
+// name-only).
+// A mixed group falls back to running entirely post-refresh, when both id and
+// attribute values are available.
+func (fg *ResourceFilterGroup) isInitial() bool {
+	for i := range fg.Filters {
+		if !fg.Filters[i].isInitial() {
+			return false
+		}
+	}
+	return true
 }
 
 func NewResource(ID, resourceName, resourceType, provider string,
@@ -99,6 +507,44 @@ func NewResource(ID, resourceName, resourceType, provider string,
 	}
 }
 
+// MergeAdditionalFields merges src into dst, recursing into nested
+// map[string]interface{} values on both sides instead of letting src blindly
+// overwrite them. Any other key in src (scalar, slice, or a type mismatch with
+// dst) simply overrides dst's value, matching plain map-assignment semantics.
+// dst is mutated and returned; pass a copy if the caller still needs the original.
+func MergeAdditionalFields(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]interface{})
+		if !srcIsMap {
+			dst[key] = srcValue
+			continue
+		}
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if !dstIsMap {
+			dstMap = map[string]interface{}{}
+		}
+		dst[key] = MergeAdditionalFields(dstMap, srcMap)
+	}
+	return dst
+}
+
+// NewResourceWithBaseFields is like NewResource, but merges additionalFields
+// on top of baseFields instead of using it as-is, so a generator can share a
+// common set of AdditionalFields across resources while still layering on
+// per-resource extras. baseFields itself is left untouched.
+func NewResourceWithBaseFields(ID, resourceName, resourceType, provider string,
+	attributes map[string]string,
+	allowEmptyValues []string,
+	baseFields map[string]interface{},
+	additionalFields map[string]interface{}) Resource {
+	merged := MergeAdditionalFields(map[string]interface{}{}, baseFields)
+	merged = MergeAdditionalFields(merged, additionalFields)
+	return NewResource(ID, resourceName, resourceType, provider, attributes, allowEmptyValues, merged)
+}
+
 func NewSimpleResource(ID, resourceName, resourceType, provider string, allowEmptyValues []string) Resource {
 	return NewResource(
 		ID,
@@ -111,17 +557,201 @@ func NewSimpleResource(ID, resourceName, resourceType, provider string, allowEmp
 	)
 }
 
-func (r *Resource) Refresh(provider *provider_wrapper.ProviderWrapper) {
+// RefreshRetries is the number of additional attempts Resource.Refresh makes after a
+// transient failure (rate limiting, timeouts, 5xx) before giving up. A user importing
+// from a heavily throttled account can raise this.
+var RefreshRetries = 3
+
+// RefreshRetryBaseDelay is the delay before the first retry; it doubles on each
+// subsequent attempt.
+var RefreshRetryBaseDelay = 500 * time.Millisecond
+
+// transientRefreshErrorPattern matches provider errors worth retrying: rate limiting,
+// timeouts, and upstream 5xx responses.
+var transientRefreshErrorPattern = regexp.MustCompile(`(?i)rate.?limit|throttl|timeout|deadline exceeded|5\d\d`)
+
+func isTransientRefreshError(err error) bool {
+	return err != nil && transientRefreshErrorPattern.MatchString(err.Error())
+}
+
+// Validate catches the common ways a generator builds a broken Resource before it
+// reaches Refresh, where the failure would otherwise show up as a silently empty
+// state and a resource that quietly disappears from the output. It checks for an
+// empty InstanceState.ID (the import ID passed to the provider), an empty
+// InstanceInfo.Type, and a resource type that doesn't start with "<provider>_", which
+// almost always means a generator copy-pasted another provider's type string.
+func (r *Resource) Validate() error {
+	if r.InstanceState == nil || r.InstanceState.ID == "" {
+		return fmt.Errorf("resource %s has an empty import ID", r.ResourceName)
+	}
+	if r.InstanceInfo == nil || r.InstanceInfo.Type == "" {
+		return fmt.Errorf("resource %s has an empty type", r.ResourceName)
+	}
+	if r.Provider != "" && !strings.HasPrefix(r.InstanceInfo.Type, r.Provider+"_") {
+		return fmt.Errorf("resource %s has type %q that doesn't match provider %q", r.ResourceName, r.InstanceInfo.Type, r.Provider)
+	}
+	return nil
+}
+
+// Refresh queries provider for the resource's current state, retrying transient
+// failures with backoff. If SlowQueryRequired or SlowQueryDelay is set, it sleeps for
+// that duration before each provider RPC to stay under a throttled API's rate limit.
+//
+// ctx is checked before each attempt and while sleeping between retries, so a
+// cancelled context (e.g. from a Ctrl-C signal handler) stops the resource promptly
+// between RPCs; the provider RPC itself, once started, still runs to completion since
+// the underlying plugin transport isn't cancellable.
+func (r *Resource) Refresh(ctx context.Context, provider *provider_wrapper.ProviderWrapper) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", r.InstanceInfo.Id, err)
+	}
+	if err := r.Validate(); err != nil {
+		return err
+	}
 	var err error
-	r.InstanceState, err = provider.Refresh(r.InstanceInfo, r.InstanceState)
-	if err != nil {
-		log.Println(err)
+	delay := RefreshRetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		if d := r.slowQueryDelay(); d > 0 {
+			if sleepErr := sleepContext(ctx, d); sleepErr != nil {
+				return fmt.Errorf("%s: %w", r.InstanceInfo.Id, sleepErr)
+			}
+		}
+		var state *terraform.InstanceState
+		state, err = provider.Refresh(ctx, r.InstanceInfo, r.InstanceState)
+		if err == nil {
+			r.InstanceState = state
+			return nil
+		}
+		if attempt >= RefreshRetries || !isTransientRefreshError(err) {
+			break
+		}
+		Logf(LogLevelWarn, "transient error refreshing %s, retrying in %s: %s", r.InstanceInfo.Id, delay, err)
+		if sleepErr := sleepContext(ctx, delay); sleepErr != nil {
+			return fmt.Errorf("%s: %w", r.InstanceInfo.Id, sleepErr)
+		}
+		delay *= 2
+	}
+	return fmt.Errorf("%s: %s", r.InstanceInfo.Id, err)
+}
+
+// sleepContext sleeps for d, returning early with ctx.Err() if ctx is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RequestedOutput names a root output a generator wants to expose for a resource, and
+// the InstanceState attribute it should reference.
+type RequestedOutput struct {
+	Name string
+	Attr string
+}
+
+// AddOutput records a request to expose attr as a root output named name, e.g.
+// r.AddOutput("arn", "arn") to expose an aws_vpc's ARN alongside its automatic id
+// output.
+func (r *Resource) AddOutput(name, attr string) {
+	r.RequestedOutputs = append(r.RequestedOutputs, RequestedOutput{Name: name, Attr: attr})
+}
+
+// AddDependsOn records that address (e.g. "aws_iam_role.foo") must be applied before
+// this resource, e.g. r.AddDependsOn("aws_iam_role_policy_attachment." + attachment.ResourceName).
+func (r *Resource) AddDependsOn(address string) {
+	r.DependsOn = append(r.DependsOn, address)
+}
+
+// AddIgnoreChanges records that attr should be excluded from drift detection, e.g.
+// r.AddIgnoreChanges("secret_string") for a secret a provider rotates outside of
+// Terraform. attr is a top-level attribute name, as IgnoreChanges lists.
+func (r *Resource) AddIgnoreChanges(attr string) {
+	r.IgnoreChanges = append(r.IgnoreChanges, attr)
+}
+
+// AddStripKeys appends an IgnoreKeys pattern that drops attr from the resource
+// entirely during ConvertTFstate, regardless of the value found there -- the mirror
+// image of AllowEmptyValues, which keeps an attribute despite an empty value. attr is a
+// literal dotted path (e.g. "metadata.annotations") rather than a regex: each segment
+// is escaped and joined with an optional numeric index, so it matches both a
+// TypeMap-style nesting ("metadata.annotations") and a TypeList-style one
+// ("metadata.0.annotations") without the caller needing to know which the provider
+// schema uses.
+func (r *Resource) AddStripKeys(attr string) {
+	parts := strings.Split(attr, ".")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	r.IgnoreKeys = append(r.IgnoreKeys, strings.Join(parts, `\.(\d+\.)?`))
+}
+
+// SetType overrides InstanceInfo.Type after construction, for a generator that only
+// knows which Terraform resource type an object maps to (e.g. a spot vs. on-demand
+// instance) once it has fetched attributes, and would otherwise need to build a
+// throwaway Resource just to pick the right type up front. t must keep the same
+// "<provider>_" prefix as the resource's current type, the same invariant Validate
+// checks, since a type swap across providers isn't something a single generator can do
+// meaningfully. InstanceInfo.Id is re-derived to match, the same way NewResource
+// derives it initially.
+func (r *Resource) SetType(t string) error {
+	if r.Provider != "" && !strings.HasPrefix(t, r.Provider+"_") {
+		return fmt.Errorf("resource %s: type %q doesn't match provider %q", r.ResourceName, t, r.Provider)
+	}
+	r.InstanceInfo.Type = t
+	r.InstanceInfo.Id = fmt.Sprintf("%s.%s", t, r.ResourceName)
+	return nil
+}
+
+// RenameAttr moves Item[oldName] to Item[newName], preserving its value and type
+// unchanged. It no-ops if oldName isn't present, so a generator's PostConvertHook can
+// call it unconditionally across schema versions instead of hand-rolling
+// GetStateAttr/SetStateAttr/DeleteStateAttr for a plain attribute rename (e.g. a
+// provider renaming "name" to "display_name" between major versions).
+func (r *Resource) RenameAttr(oldName, newName string) {
+	value, exists := r.Item[oldName]
+	if !exists {
+		return
+	}
+	delete(r.Item, oldName)
+	r.Item[newName] = value
+}
+
+// Clone deep-copies r, so a generator deriving a sibling resource (e.g. an IAM policy
+// attachment from the role it attaches to) can safely mutate the copy's
+// InstanceState, AdditionalFields, IgnoreKeys, and AllowEmptyValues without the two
+// resources aliasing each other's maps and slices.
+func (r Resource) Clone() Resource {
+	clone := r
+	if r.InstanceState != nil {
+		clone.InstanceState = r.InstanceState.DeepCopy()
 	}
+	if r.AdditionalFields != nil {
+		clone.AdditionalFields = MergeAdditionalFields(map[string]interface{}{}, r.AdditionalFields)
+	}
+	if r.IgnoreKeys != nil {
+		clone.IgnoreKeys = append([]string{}, r.IgnoreKeys...)
+	}
+	if r.AllowEmptyValues != nil {
+		clone.AllowEmptyValues = append([]string{}, r.AllowEmptyValues...)
+	}
+	return clone
 }
 
+// GetIDKey returns the first attribute in IDKeyPriority (or DefaultIDKeyPriority, if
+// unset) that exists in InstanceState.Attributes, falling back to "id" if none match.
 func (r Resource) GetIDKey() string {
-	if _, exist := r.InstanceState.Attributes["self_link"]; exist {
-		return "self_link"
+	priority := r.IDKeyPriority
+	if len(priority) == 0 {
+		priority = DefaultIDKeyPriority
+	}
+	for _, key := range priority {
+		if _, exist := r.InstanceState.Attributes[key]; exist {
+			return key
+		}
 	}
 	return "id"
 }
@@ -153,12 +783,15 @@ func (r *Resource) ConvertTFstate(provider *provider_wrapper.ProviderWrapper) er
 		}
 	}
 	parser := NewFlatmapParser(r.InstanceState.Attributes, ignoreKeys, allowEmptyValues)
-	schema := provider.Provider.GetSchema()
+	schema := provider.GetSchema()
 	impliedType := schema.ResourceTypes[r.InstanceInfo.Type].Block.ImpliedType()
 	return r.ParseTFstate(parser, impliedType)
 }
 
-// isAllowedEmptyValue checks if a key is an allowed empty value with regular expression
+// isAllowedEmptyValue checks if a key is an allowed empty value with regular expression.
+// Patterns are matched unanchored, so "rule.*.action" already covers every repeated
+// nested block ("rule.0.action", "rule.1.action", ...) without needing separate glob
+// support, alongside plain prefix patterns like "tags.".
 func (r *Resource) isAllowedEmptyValue(key string) bool {
 	for _, pattern := range r.AllowEmptyValues {
 		match, err := regexp.MatchString(pattern, key)
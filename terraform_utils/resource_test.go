@@ -0,0 +1,313 @@
+package terraform_utils
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestGetIDKeyDefaultsToSelfLinkThenID(t *testing.T) {
+	withSelfLink := Resource{InstanceState: &terraform.InstanceState{Attributes: map[string]string{"self_link": "x", "id": "y"}}}
+	if key := withSelfLink.GetIDKey(); key != "self_link" {
+		t.Fatalf("GetIDKey() = %q, want %q", key, "self_link")
+	}
+
+	withoutSelfLink := Resource{InstanceState: &terraform.InstanceState{Attributes: map[string]string{"id": "y"}}}
+	if key := withoutSelfLink.GetIDKey(); key != "id" {
+		t.Fatalf("GetIDKey() = %q, want %q", key, "id")
+	}
+}
+
+func TestGetIDKeyHonorsCustomPriority(t *testing.T) {
+	resource := Resource{
+		IDKeyPriority: []string{"arn", "id"},
+		InstanceState: &terraform.InstanceState{Attributes: map[string]string{"self_link": "x", "arn": "a", "id": "y"}},
+	}
+	if key := resource.GetIDKey(); key != "arn" {
+		t.Fatalf("GetIDKey() = %q, want %q", key, "arn")
+	}
+}
+
+func TestMergeAdditionalFieldsRecursesNestedMaps(t *testing.T) {
+	dst := map[string]interface{}{
+		"tags": map[string]interface{}{
+			"env":  "prod",
+			"team": "core",
+		},
+		"region": "us-east-1",
+	}
+	src := map[string]interface{}{
+		"tags": map[string]interface{}{
+			"team": "platform",
+			"tier": "1",
+		},
+	}
+
+	merged := MergeAdditionalFields(dst, src)
+
+	want := map[string]interface{}{
+		"tags": map[string]interface{}{
+			"env":  "prod",
+			"team": "platform",
+			"tier": "1",
+		},
+		"region": "us-east-1",
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("MergeAdditionalFields() = %#v, want %#v", merged, want)
+	}
+}
+
+func TestMergeAdditionalFieldsSrcOverridesScalarWithMap(t *testing.T) {
+	dst := map[string]interface{}{"tags": "none"}
+	src := map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}}
+
+	merged := MergeAdditionalFields(dst, src)
+
+	want := map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}}
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("MergeAdditionalFields() = %#v, want %#v", merged, want)
+	}
+}
+
+func TestResourceValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		resource Resource
+		wantErr  bool
+	}{
+		{
+			name: "valid",
+			resource: Resource{
+				ResourceName:  "test",
+				Provider:      "aws",
+				InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+				InstanceState: &terraform.InstanceState{ID: "i-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty import ID",
+			resource: Resource{
+				ResourceName:  "test",
+				Provider:      "aws",
+				InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+				InstanceState: &terraform.InstanceState{ID: ""},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty type",
+			resource: Resource{
+				ResourceName:  "test",
+				Provider:      "aws",
+				InstanceInfo:  &terraform.InstanceInfo{Type: ""},
+				InstanceState: &terraform.InstanceState{ID: "i-1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "provider mismatch",
+			resource: Resource{
+				ResourceName:  "test",
+				Provider:      "aws",
+				InstanceInfo:  &terraform.InstanceInfo{Type: "google_compute_instance"},
+				InstanceState: &terraform.InstanceState{ID: "i-1"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.resource.Validate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceAddOutput(t *testing.T) {
+	r := Resource{}
+	r.AddOutput("arn", "arn")
+
+	want := []RequestedOutput{{Name: "arn", Attr: "arn"}}
+	if !reflect.DeepEqual(r.RequestedOutputs, want) {
+		t.Fatalf("RequestedOutputs = %#v, want %#v", r.RequestedOutputs, want)
+	}
+}
+
+func TestRenameAttrMovesValue(t *testing.T) {
+	r := Resource{Item: map[string]interface{}{"name": "example"}}
+
+	r.RenameAttr("name", "display_name")
+
+	if _, exists := r.Item["name"]; exists {
+		t.Errorf("expected \"name\" to be removed, got %v", r.Item["name"])
+	}
+	if r.Item["display_name"] != "example" {
+		t.Errorf("expected display_name=example, got %v", r.Item["display_name"])
+	}
+}
+
+func TestRenameAttrNoopsWhenMissing(t *testing.T) {
+	r := Resource{Item: map[string]interface{}{"other": "value"}}
+
+	r.RenameAttr("name", "display_name")
+
+	want := map[string]interface{}{"other": "value"}
+	if !reflect.DeepEqual(r.Item, want) {
+		t.Errorf("expected Item to be unchanged, got %v", r.Item)
+	}
+}
+
+func TestAddStripKeysMatchesBothMapAndListNesting(t *testing.T) {
+	r := Resource{}
+	r.AddStripKeys("metadata.annotations")
+
+	if len(r.IgnoreKeys) != 1 {
+		t.Fatalf("expected 1 IgnoreKeys pattern, got %v", r.IgnoreKeys)
+	}
+	pattern := regexp.MustCompile(r.IgnoreKeys[0])
+	if !pattern.MatchString("metadata.annotations.foo") {
+		t.Errorf("expected pattern %q to match a TypeMap-style nested key", r.IgnoreKeys[0])
+	}
+	if !pattern.MatchString("metadata.0.annotations.foo") {
+		t.Errorf("expected pattern %q to match a TypeList-style nested key", r.IgnoreKeys[0])
+	}
+	if pattern.MatchString("metadata.other") {
+		t.Errorf("expected pattern %q not to match an unrelated key", r.IgnoreKeys[0])
+	}
+}
+
+func TestCloneDoesNotAliasOriginal(t *testing.T) {
+	r := Resource{
+		InstanceState:    &terraform.InstanceState{ID: "i-1", Attributes: map[string]string{"name": "orig"}},
+		AdditionalFields: map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}},
+		IgnoreKeys:       []string{"metadata.annotations"},
+		AllowEmptyValues: []string{"description"},
+	}
+
+	clone := r.Clone()
+	clone.InstanceState.Attributes["name"] = "changed"
+	clone.AdditionalFields["tags"].(map[string]interface{})["env"] = "staging"
+	clone.IgnoreKeys[0] = "changed"
+	clone.AllowEmptyValues[0] = "changed"
+
+	if r.InstanceState.Attributes["name"] != "orig" {
+		t.Errorf("mutating clone's InstanceState changed the original, got %v", r.InstanceState.Attributes["name"])
+	}
+	if r.AdditionalFields["tags"].(map[string]interface{})["env"] != "prod" {
+		t.Errorf("mutating clone's AdditionalFields changed the original, got %v", r.AdditionalFields["tags"])
+	}
+	if r.IgnoreKeys[0] != "metadata.annotations" {
+		t.Errorf("mutating clone's IgnoreKeys changed the original, got %v", r.IgnoreKeys)
+	}
+	if r.AllowEmptyValues[0] != "description" {
+		t.Errorf("mutating clone's AllowEmptyValues changed the original, got %v", r.AllowEmptyValues)
+	}
+}
+
+func TestAddDataFileRecordsContentAndCompressFlag(t *testing.T) {
+	r := Resource{}
+	r.AddDataFile("zip", []byte("payload"), true)
+
+	df, ok := r.DataFiles["zip"]
+	if !ok {
+		t.Fatalf("expected DataFiles[%q] to be set, got %#v", "zip", r.DataFiles)
+	}
+	if string(df.Content) != "payload" {
+		t.Errorf("Content = %q, want %q", df.Content, "payload")
+	}
+	if !df.Compress {
+		t.Errorf("expected Compress to be true")
+	}
+}
+
+func TestDataFilePathAddsGzSuffixOnlyWhenCompressed(t *testing.T) {
+	r := Resource{ResourceName: "myFunction"}
+	r.AddDataFile("zip", []byte("payload"), false)
+	r.AddDataFile("cert.pem", []byte("cert"), true)
+
+	if got, want := r.DataFilePath("zip"), "data/myFunction/zip"; got != want {
+		t.Errorf("DataFilePath(zip) = %q, want %q", got, want)
+	}
+	if got, want := r.DataFilePath("cert.pem"), "data/myFunction/cert.pem.gz"; got != want {
+		t.Errorf("DataFilePath(cert.pem) = %q, want %q", got, want)
+	}
+}
+
+func TestFormattedImportIDDefaultsToImportID(t *testing.T) {
+	r := Resource{InstanceState: &terraform.InstanceState{ID: "raw-id"}}
+
+	if got, want := r.FormattedImportID(), "raw-id"; got != want {
+		t.Errorf("FormattedImportID() = %q, want %q", got, want)
+	}
+}
+
+func TestFormattedImportIDAppliesFormatter(t *testing.T) {
+	r := Resource{
+		InstanceState: &terraform.InstanceState{ID: "raw-id"},
+		ImportIDFormatter: func(id string) string {
+			return "zone1/" + id
+		},
+	}
+
+	if got, want := r.FormattedImportID(), "zone1/raw-id"; got != want {
+		t.Errorf("FormattedImportID() = %q, want %q", got, want)
+	}
+}
+
+func TestSetTypeUpdatesInstanceInfo(t *testing.T) {
+	r := NewResource("i-1", "myInstance", "aws_instance", "aws", map[string]string{}, []string{}, map[string]interface{}{})
+
+	if err := r.SetType("aws_spot_instance_request"); err != nil {
+		t.Fatalf("SetType() error = %v", err)
+	}
+
+	if r.InstanceInfo.Type != "aws_spot_instance_request" {
+		t.Errorf("InstanceInfo.Type = %q, want %q", r.InstanceInfo.Type, "aws_spot_instance_request")
+	}
+	if want := "aws_spot_instance_request." + r.ResourceName; r.InstanceInfo.Id != want {
+		t.Errorf("InstanceInfo.Id = %q, want %q", r.InstanceInfo.Id, want)
+	}
+}
+
+func TestSetTypeRejectsProviderMismatch(t *testing.T) {
+	r := NewResource("i-1", "myInstance", "aws_instance", "aws", map[string]string{}, []string{}, map[string]interface{}{})
+
+	if err := r.SetType("google_compute_instance"); err == nil {
+		t.Fatal("expected an error for a type that doesn't match the resource's provider")
+	}
+	if r.InstanceInfo.Type != "aws_instance" {
+		t.Errorf("InstanceInfo.Type should be unchanged after a rejected SetType, got %q", r.InstanceInfo.Type)
+	}
+}
+
+func TestNewResourceWithBaseFieldsLayersOnTopOfBase(t *testing.T) {
+	base := map[string]interface{}{
+		"tags": map[string]interface{}{"env": "prod"},
+	}
+	resource := NewResourceWithBaseFields(
+		"i-1", "test", "aws_instance", "aws",
+		map[string]string{},
+		[]string{},
+		base,
+		map[string]interface{}{
+			"tags": map[string]interface{}{"team": "core"},
+		},
+	)
+
+	want := map[string]interface{}{
+		"tags": map[string]interface{}{"env": "prod", "team": "core"},
+	}
+	if !reflect.DeepEqual(resource.AdditionalFields, want) {
+		t.Fatalf("AdditionalFields = %#v, want %#v", resource.AdditionalFields, want)
+	}
+	if _, ok := base["tags"].(map[string]interface{})["team"]; ok {
+		t.Fatalf("base fields were mutated: %#v", base)
+	}
+}
@@ -17,6 +17,7 @@ package terraform_utils
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 )
@@ -25,8 +26,9 @@ type ServiceGenerator interface {
 	InitResources() error
 	GetResources() []Resource
 	SetResources(resources []Resource)
-	ParseFilter(rawFilter string) []ResourceFilter
+	ParseFilter(rawFilter string) []ResourceFilterGroup
 	ParseFilters(rawFilters []string)
+	AddFilters(filters []ResourceFilter)
 	PostConvertHook() error
 	GetArgs() map[string]interface{}
 	SetArgs(args map[string]interface{})
@@ -36,6 +38,8 @@ type ServiceGenerator interface {
 	InitialCleanup()
 	PopulateIgnoreKeys(cty.Value)
 	PostRefreshCleanup()
+	SetRevealSensitiveValues(reveal bool)
+	SetSince(since time.Time)
 }
 
 type Service struct {
@@ -43,7 +47,55 @@ type Service struct {
 	Resources    []Resource
 	ProviderName string
 	Args         map[string]interface{}
-	Filter       []ResourceFilter
+	Filter       []ResourceFilterGroup
+	// ValueRewriter, if set, normalizes every attribute value across all resources
+	// during the base PostConvertHook. A generator with a custom PostConvertHook that
+	// still wants this behavior should call ApplyValueRewriter itself.
+	ValueRewriter ValueRewriter
+	// RevealSensitiveValues disables the base PostConvertHook's default redaction of
+	// each resource's SensitiveAttrs, letting a user opt back into emitting raw
+	// secrets when they've decided the risk is acceptable for their workflow.
+	RevealSensitiveValues bool
+	// Since, if non-zero, restricts import to resources created or modified at or
+	// after this time. There's no single API-agnostic way to apply this, so it's
+	// exposed for individual generators to consult: some can turn it into a
+	// server-side list filter, others must fall back to a client-side check (see
+	// FilterResourcesSince) against a creation/modification timestamp attribute set
+	// during InitResources.
+	Since time.Time
+}
+
+// SetRevealSensitiveValues opts back into emitting raw SensitiveAttrs values instead
+// of the base PostConvertHook's default redaction.
+func (s *Service) SetRevealSensitiveValues(reveal bool) {
+	s.RevealSensitiveValues = reveal
+}
+
+// SetSince restricts InitResources to resources created or modified at or after since.
+func (s *Service) SetSince(since time.Time) {
+	s.Since = since
+}
+
+// RedactSensitiveAttrs runs Resource.RedactSensitiveAttrs over every resource, unless
+// RevealSensitiveValues is set.
+func (s *Service) RedactSensitiveAttrs() {
+	if s.RevealSensitiveValues {
+		return
+	}
+	for i := range s.Resources {
+		s.Resources[i].RedactSensitiveAttrs()
+	}
+}
+
+// ApplyValueRewriter runs Service.ValueRewriter over every resource's parsed state, if
+// one is set.
+func (s *Service) ApplyValueRewriter() {
+	if s.ValueRewriter == nil {
+		return
+	}
+	for i := range s.Resources {
+		s.Resources[i].RewriteValues(s.ValueRewriter)
+	}
 }
 
 func (s *Service) SetProviderName(providerName string) {
@@ -51,30 +103,60 @@ func (s *Service) SetProviderName(providerName string) {
 }
 
 func (s *Service) ParseFilters(rawFilters []string) {
-	s.Filter = []ResourceFilter{}
+	s.Filter = []ResourceFilterGroup{}
 	for _, rawFilter := range rawFilters {
-		filters := s.ParseFilter(rawFilter)
-		for _, resourceFilter := range filters {
-			s.Filter = append(s.Filter, resourceFilter)
+		s.Filter = append(s.Filter, s.ParseFilter(rawFilter)...)
+	}
+}
+
+// AddFilters appends filters loaded from a file (see LoadFilters) to s.Filter, one
+// AND-group of one clause per filter, matching how ParseFilter treats a single-clause
+// --filter flag.
+func (s *Service) AddFilters(filters []ResourceFilter) {
+	for _, filter := range filters {
+		s.Filter = append(s.Filter, ResourceFilterGroup{Filters: []ResourceFilter{filter}})
+	}
+}
+
+// ParseFilter parses a single --filter flag into a ResourceFilterGroup. Clauses
+// separated by "||" are ORed together; a filter with a single clause is an AND-group
+// of one, which is exactly today's behavior.
+func (s *Service) ParseFilter(rawFilter string) []ResourceFilterGroup {
+	clauses := strings.Split(rawFilter, "||")
+	group := ResourceFilterGroup{Logic: FilterLogicAnd}
+	if len(clauses) > 1 {
+		group.Logic = FilterLogicOr
+	}
+	for _, clause := range clauses {
+		filter, ok := parseFilterClause(clause)
+		if !ok {
+			continue
 		}
+		group.Filters = append(group.Filters, filter)
+	}
+	if len(group.Filters) == 0 {
+		return nil
 	}
+	return []ResourceFilterGroup{group}
 }
 
-func (s *Service) ParseFilter(rawFilter string) []ResourceFilter {
-	var filters []ResourceFilter
+func parseFilterClause(rawFilter string) (ResourceFilter, bool) {
+	negate := strings.HasPrefix(rawFilter, "!")
+	rawFilter = strings.TrimPrefix(rawFilter, "!")
+	var filter ResourceFilter
 	if len(strings.Split(rawFilter, "=")) == 2 {
 		parts := strings.Split(rawFilter, "=")
 		resourceName, resourcesID := parts[0], parts[1]
-		filters = append(filters, ResourceFilter{
+		filter = ResourceFilter{
 			ResourceName:     resourceName,
 			FieldPath:        "id",
 			AcceptableValues: ParseFilterValues(resourcesID),
-		})
+		}
 	} else {
 		parts := strings.Split(rawFilter, ";")
 		if len(parts) != 2 && len(parts) != 3 {
 			log.Print("Invalid filter: " + rawFilter)
-			return filters
+			return ResourceFilter{}, false
 		}
 		var ResourceNamePart string
 		var FieldPathPart string
@@ -89,13 +171,28 @@ func (s *Service) ParseFilter(rawFilter string) []ResourceFilter {
 			AcceptableValuesPart = parts[2]
 		}
 
-		filters = append(filters, ResourceFilter{
+		fieldPath := strings.TrimPrefix(FieldPathPart, "Name=")
+		if ResourceNamePart == tagFilterType {
+			// "Type=tag;Name=<key>;Value=<value>" matches a tag key/value pair
+			// regardless of how the resource represents its tags.
+			ResourceNamePart = ""
+			fieldPath = tagFieldPathPrefix + fieldPath
+		}
+
+		filter = ResourceFilter{
 			ResourceName:     ResourceNamePart,
-			FieldPath:        strings.TrimPrefix(FieldPathPart, "Name="),
+			FieldPath:        fieldPath,
 			AcceptableValues: ParseFilterValues(strings.TrimPrefix(AcceptableValuesPart, "Value=")),
-		})
+		}
+	}
+	if err := filter.compileValueRegexps(); err != nil {
+		log.Fatal(err)
+	}
+	if err := filter.compileComparisons(); err != nil {
+		log.Fatal(err)
 	}
-	return filters
+	filter.Negate = negate
+	return filter, true
 }
 
 func (s *Service) SetName(name string) {
@@ -134,6 +231,8 @@ func (s *Service) InitResources() error {
 }
 
 func (s *Service) PostConvertHook() error {
+	s.ApplyValueRewriter()
+	s.RedactSensitiveAttrs()
 	return nil
 }
 
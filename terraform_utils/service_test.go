@@ -2,7 +2,9 @@ package terraform_utils
 
 import (
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/zclconf/go-cty/cty"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -10,7 +12,7 @@ func TestEmptyFiltersParsing(t *testing.T) {
 	service := Service{}
 	service.ParseFilters([]string{})
 
-	if !reflect.DeepEqual(service.Filter, []ResourceFilter{}) {
+	if !reflect.DeepEqual(service.Filter, []ResourceFilterGroup{}) {
 		t.Errorf("failed to parse, got %v", service.Filter)
 	}
 }
@@ -19,11 +21,14 @@ func TestIdFiltersParsing(t *testing.T) {
 	service := Service{}
 	service.ParseFilters([]string{"aws_vpc=myid"})
 
-	if !reflect.DeepEqual(service.Filter, []ResourceFilter{
+	if !reflect.DeepEqual(service.Filter, []ResourceFilterGroup{
 		{
-			ResourceName:     "aws_vpc",
-			FieldPath:        "id",
-			AcceptableValues: []string{"myid"},
+			Logic: FilterLogicAnd,
+			Filters: []ResourceFilter{{
+				ResourceName:     "aws_vpc",
+				FieldPath:        "id",
+				AcceptableValues: []string{"myid"},
+			}},
 		}}) {
 		t.Errorf("failed to parse, got %v", service.Filter)
 	}
@@ -33,11 +38,14 @@ func TestComplexIdFiltersParsing(t *testing.T) {
 	service := Service{}
 	service.ParseFilters([]string{"resource=id1:'project:dataset_id'"})
 
-	if !reflect.DeepEqual(service.Filter, []ResourceFilter{
+	if !reflect.DeepEqual(service.Filter, []ResourceFilterGroup{
 		{
-			ResourceName:     "resource",
-			FieldPath:        "id",
-			AcceptableValues: []string{"id1", "project:dataset_id"},
+			Logic: FilterLogicAnd,
+			Filters: []ResourceFilter{{
+				ResourceName:     "resource",
+				FieldPath:        "id",
+				AcceptableValues: []string{"id1", "project:dataset_id"},
+			}},
 		}}) {
 		t.Errorf("failed to parse, got %v", service.Filter)
 	}
@@ -47,11 +55,36 @@ func TestEdgeIdFiltersParsing(t *testing.T) {
 	service := Service{}
 	service.ParseFilters([]string{"aws_vpc=:myid"})
 
-	if !reflect.DeepEqual(service.Filter, []ResourceFilter{
+	if !reflect.DeepEqual(service.Filter, []ResourceFilterGroup{
 		{
-			ResourceName:     "aws_vpc",
-			FieldPath:        "id",
-			AcceptableValues: []string{"myid"},
+			Logic: FilterLogicAnd,
+			Filters: []ResourceFilter{{
+				ResourceName:     "aws_vpc",
+				FieldPath:        "id",
+				AcceptableValues: []string{"myid"},
+			}},
+		}}) {
+		t.Errorf("failed to parse, got %v", service.Filter)
+	}
+}
+
+func TestOrFiltersParsing(t *testing.T) {
+	service := Service{}
+	service.ParseFilters([]string{"Name=tags.Env;Value=dev||Name=tags.Env;Value=staging"})
+
+	if !reflect.DeepEqual(service.Filter, []ResourceFilterGroup{
+		{
+			Logic: FilterLogicOr,
+			Filters: []ResourceFilter{
+				{
+					FieldPath:        "tags.Env",
+					AcceptableValues: []string{"dev"},
+				},
+				{
+					FieldPath:        "tags.Env",
+					AcceptableValues: []string{"staging"},
+				},
+			},
 		}}) {
 		t.Errorf("failed to parse, got %v", service.Filter)
 	}
@@ -81,6 +114,252 @@ func TestServiceIdCleanupWithFilter(t *testing.T) {
 	}
 }
 
+func TestServiceIdCleanupWithFilterHonorsImportIDOverride(t *testing.T) {
+	service := Service{
+		Resources: []Resource{{
+			InstanceInfo: &terraform.InstanceInfo{
+				Type: "type1",
+			},
+			InstanceState:    &terraform.InstanceState{ID: "raw-id"},
+			ImportIDOverride: "composite-id",
+		}, {
+			InstanceInfo:  &terraform.InstanceInfo{Type: "type1"},
+			InstanceState: &terraform.InstanceState{ID: "raw-id"},
+		}},
+	}
+	service.ParseFilters([]string{"type1=:composite-id"})
+	service.InitialCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 1) {
+		t.Fatalf("failed to cleanup, got %v", service.Resources)
+	}
+	if service.Resources[0].ImportIDOverride != "composite-id" {
+		t.Errorf("id filter kept the wrong resource, got %v", service.Resources[0])
+	}
+}
+
+func TestServiceIdCleanupWithNegatedFilter(t *testing.T) {
+	service := Service{
+		Resources: []Resource{{
+			InstanceInfo: &terraform.InstanceInfo{
+				Type: "type1",
+			},
+			InstanceState: &terraform.InstanceState{
+				ID: "myid",
+			}}, {
+			InstanceInfo: &terraform.InstanceInfo{
+				Type: "type1",
+			},
+			InstanceState: &terraform.InstanceState{
+				ID: "otherId",
+			}}},
+	}
+	service.ParseFilters([]string{"!type1=:otherId"})
+	service.InitialCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 1) {
+		t.Errorf("failed to cleanup")
+	}
+	if service.Resources[0].InstanceState.ID != "myid" {
+		t.Errorf("negated filter excluded the wrong resource, got %v", service.Resources[0].InstanceState.ID)
+	}
+}
+
+func TestServiceAttributeCleanupWithComparisonFilter(t *testing.T) {
+	service := Service{
+		Resources: []Resource{
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Type: "aws_instance",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "i1",
+				},
+				Item: mapI("disk_size", "50")},
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Type: "aws_instance",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "i2",
+				},
+				Item: mapI("disk_size", "200")}},
+	}
+	service.ParseFilters([]string{"Name=disk_size;Value=>100"})
+	service.PostRefreshCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 1) {
+		t.Errorf("failed to cleanup")
+	}
+	if service.Resources[0].InstanceState.ID != "i2" {
+		t.Errorf("comparison filter kept the wrong resource, got %v", service.Resources[0].InstanceState.ID)
+	}
+}
+
+func TestServiceAttributeCleanupWithRegexFilter(t *testing.T) {
+	service := Service{
+		Resources: []Resource{
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Type: "aws_vpc",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "vpc1",
+				},
+				Item: mapI("tags", mapI("Name", "prod-vpc"))},
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Type: "aws_vpc",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "vpc2",
+				},
+				Item: mapI("tags", mapI("Name", "staging-vpc"))}},
+	}
+	service.ParseFilters([]string{"Name=tags.Name;Value=~^prod-.*"})
+	service.PostRefreshCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 1) {
+		t.Errorf("failed to cleanup")
+	}
+}
+
+func TestServiceAttributeCleanupWithOrFilter(t *testing.T) {
+	service := Service{
+		Resources: []Resource{
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Id:   "aws_vpc.vpc1",
+					Type: "aws_vpc",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "vpc1",
+				},
+				Item: mapI("tags", mapI("Env", "dev"))},
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Id:   "aws_vpc.vpc2",
+					Type: "aws_vpc",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "vpc2",
+				},
+				Item: mapI("tags", mapI("Env", "staging"))},
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Id:   "aws_vpc.vpc3",
+					Type: "aws_vpc",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "vpc3",
+				},
+				Item: mapI("tags", mapI("Env", "prod"))}},
+	}
+	service.ParseFilters([]string{"Name=tags.Env;Value=dev||Name=tags.Env;Value=staging"})
+	service.PostRefreshCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 2) {
+		t.Errorf("failed to cleanup, got %v", service.Resources)
+	}
+}
+
+func TestServiceAttributeCleanupWithBooleanFilter(t *testing.T) {
+	service := Service{
+		Resources: []Resource{
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Type: "aws_instance",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "i1",
+				},
+				Item: mapI("monitoring", cty.True)},
+			{
+				InstanceInfo: &terraform.InstanceInfo{
+					Type: "aws_instance",
+				},
+				InstanceState: &terraform.InstanceState{
+					ID: "i2",
+				},
+				Item: mapI("monitoring", cty.False)}},
+	}
+	service.ParseFilters([]string{"Name=monitoring;Value=true"})
+	service.PostRefreshCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 1) {
+		t.Errorf("failed to cleanup")
+	}
+	if service.Resources[0].InstanceState.ID != "i1" {
+		t.Errorf("boolean filter kept the wrong resource, got %v", service.Resources[0].InstanceState.ID)
+	}
+}
+
+func TestServicePostConvertHookAppliesValueRewriter(t *testing.T) {
+	service := Service{
+		Resources: []Resource{{
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i1"},
+			Item:          mapI("name", "web"),
+		}},
+		ValueRewriter: func(path string, val cty.Value) cty.Value {
+			if path == "name" {
+				return cty.StringVal(strings.ToUpper(val.AsString()))
+			}
+			return val
+		},
+	}
+
+	if err := service.PostConvertHook(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	value, ok := service.Resources[0].GetStateAttrPath("name")
+	if !ok || value.AsString() != "WEB" {
+		t.Errorf("expected name=WEB, got %v, %v", value, ok)
+	}
+}
+
+func TestServicePostConvertHookRedactsSensitiveAttrs(t *testing.T) {
+	service := Service{
+		Resources: []Resource{{
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_db_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i1"},
+			Item:          mapI("password", "hunter2"),
+		}},
+	}
+	service.Resources[0].MarkSensitive("password")
+
+	if err := service.PostConvertHook(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	value, ok := service.Resources[0].GetStateAttr("password")
+	if !ok || value != SensitiveValuePlaceholder {
+		t.Errorf("expected password to be redacted, got %v, %v", value, ok)
+	}
+}
+
+func TestServicePostConvertHookHonorsRevealSensitiveValues(t *testing.T) {
+	service := Service{
+		RevealSensitiveValues: true,
+		Resources: []Resource{{
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_db_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i1"},
+			Item:          mapI("password", "hunter2"),
+		}},
+	}
+	service.Resources[0].MarkSensitive("password")
+
+	if err := service.PostConvertHook(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	value, ok := service.Resources[0].GetStateAttr("password")
+	if !ok || value != "hunter2" {
+		t.Errorf("expected password to stay raw, got %v, %v", value, ok)
+	}
+}
+
 func TestServiceAttributeCleanupWithFilter(t *testing.T) {
 	service := Service{
 		Resources: []Resource{
@@ -108,3 +387,86 @@ func TestServiceAttributeCleanupWithFilter(t *testing.T) {
 		t.Errorf("failed to cleanup")
 	}
 }
+
+func TestTagFiltersParsing(t *testing.T) {
+	service := Service{}
+	service.ParseFilters([]string{"Type=tag;Name=Environment;Value=prod"})
+
+	if !reflect.DeepEqual(service.Filter, []ResourceFilterGroup{
+		{
+			Logic: FilterLogicAnd,
+			Filters: []ResourceFilter{{
+				ResourceName:     "",
+				FieldPath:        "tag:Environment",
+				AcceptableValues: []string{"prod"},
+			}},
+		}}) {
+		t.Errorf("failed to parse, got %v", service.Filter)
+	}
+}
+
+func TestServiceIdCleanupWithNameFilter(t *testing.T) {
+	service := Service{
+		Resources: []Resource{{
+			ResourceName: "my-bucket",
+			InstanceInfo: &terraform.InstanceInfo{
+				Type: "type1",
+			},
+			InstanceState: &terraform.InstanceState{
+				ID: "myid",
+			}}, {
+			ResourceName: "other-bucket",
+			InstanceInfo: &terraform.InstanceInfo{
+				Type: "type1",
+			},
+			InstanceState: &terraform.InstanceState{
+				ID: "otherid",
+			}}},
+	}
+	service.ParseFilters([]string{"Name=__name__;Value=my-bucket"})
+	service.InitialCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 1) {
+		t.Fatalf("failed to cleanup, got %v", service.Resources)
+	}
+	if service.Resources[0].ResourceName != "my-bucket" {
+		t.Errorf("name filter kept the wrong resource, got %v", service.Resources[0].ResourceName)
+	}
+}
+
+func TestServiceAttributeCleanupWithTagFilterAcrossShapes(t *testing.T) {
+	service := Service{
+		Resources: []Resource{
+			{
+				InstanceInfo:  &terraform.InstanceInfo{Type: "aws_vpc", Id: "aws_vpc.vpc1"},
+				InstanceState: &terraform.InstanceState{ID: "vpc1"},
+				Item:          mapI("tags", mapI("Environment", "prod")),
+			},
+			{
+				InstanceInfo:  &terraform.InstanceInfo{Type: "google_compute_instance", Id: "google_compute_instance.instance1"},
+				InstanceState: &terraform.InstanceState{ID: "instance1"},
+				Item:          mapI("labels", mapI("Environment", "prod")),
+			},
+			{
+				InstanceInfo:  &terraform.InstanceInfo{Type: "aws_autoscaling_group", Id: "aws_autoscaling_group.asg1"},
+				InstanceState: &terraform.InstanceState{ID: "asg1"},
+				Item: map[string]interface{}{
+					"tag": []interface{}{
+						map[string]interface{}{"key": "Environment", "value": "prod"},
+					},
+				},
+			},
+			{
+				InstanceInfo:  &terraform.InstanceInfo{Type: "aws_vpc", Id: "aws_vpc.vpc2"},
+				InstanceState: &terraform.InstanceState{ID: "vpc2"},
+				Item:          mapI("tags", mapI("Environment", "staging")),
+			},
+		},
+	}
+	service.ParseFilters([]string{"Type=tag;Name=Environment;Value=prod"})
+	service.PostRefreshCleanup()
+
+	if !reflect.DeepEqual(len(service.Resources), 3) {
+		t.Errorf("failed to cleanup, got %d resources", len(service.Resources))
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform_utils
+
+import (
+	"log"
+	"time"
+)
+
+// FilterResourcesSince drops resources whose timeAttr attribute parses to a time
+// before since, for generators whose list API has no server-side "since" filter and
+// must fall back to a client-side check after InitResources. since being the zero
+// value is treated as "unset" and returns resources unchanged. A resource missing
+// timeAttr, or whose value fails to parse as RFC3339, is kept rather than dropped: an
+// unreadable timestamp shouldn't silently hide a resource from the user.
+func FilterResourcesSince(resources []Resource, since time.Time, timeAttr string) []Resource {
+	if since.IsZero() {
+		return resources
+	}
+	var filtered []Resource
+	for _, r := range resources {
+		raw, ok := r.InstanceState.Attributes[timeAttr]
+		if !ok {
+			filtered = append(filtered, r)
+			continue
+		}
+		modified, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			log.Println(err)
+			filtered = append(filtered, r)
+			continue
+		}
+		if !modified.Before(since) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,482 @@
+// Copyright 2019 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraform_utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
+)
+
+// GetStateAttr returns the value at the given dotted path in the resource's parsed
+// state (Item). The second return reports whether the path resolved to a single
+// string value.
+func (r *Resource) GetStateAttr(attr string) (string, bool) {
+	vals := WalkAndGet(attr, r.Item)
+	if len(vals) != 1 {
+		return "", false
+	}
+	str, ok := vals[0].(string)
+	if !ok {
+		return "", false
+	}
+	return str, true
+}
+
+// HasStateAttr reports whether attr resolves to a value in the resource's parsed state.
+// It's nil-safe: a resource whose Item was never populated (e.g. a refresh that failed
+// before ConvertTFstate ran) reports false rather than panicking, the same as every
+// other *StateAttr accessor.
+func (r *Resource) HasStateAttr(attr string) bool {
+	if r == nil || r.Item == nil {
+		return false
+	}
+	if _, ok := lookupRawAttr(r.Item, strings.Split(attr, ".")); ok {
+		return true
+	}
+	_, ok := r.GetStateAttrPath(strings.Split(attr, ".")...)
+	return ok
+}
+
+// GetStateAttrBool is like GetStateAttr, but parses the attribute as a boolean.
+// It returns false, false when the attribute is missing or isn't a valid boolean.
+func (r *Resource) GetStateAttrBool(attr string) (bool, bool) {
+	str, ok := r.GetStateAttr(attr)
+	if !ok {
+		return false, false
+	}
+	value, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, false
+	}
+	return value, true
+}
+
+// GetStateAttrInt is like GetStateAttr, but parses the attribute as an integer.
+// It returns 0, false when the attribute is missing or isn't a valid integer.
+func (r *Resource) GetStateAttrInt(attr string) (int64, bool) {
+	str, ok := r.GetStateAttr(attr)
+	if !ok {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// GetStateAttrPath walks an arbitrary-depth path into the resource's parsed state,
+// indexing into lists at numeric-looking segments, and returns the resolved value as
+// a cty.Value. The path has to resolve to exactly one value: a list attribute named
+// without an index reports false unless it holds a single element, which is then
+// returned on its own. HasStateAttr builds on it, so it's deliberately nil-safe on
+// both r and r.Item: a resource a failed refresh never reached ConvertTFstate for has
+// no Item yet, and a PostConvertHook that checks HasStateAttr before touching such a
+// resource shouldn't panic for it.
+func (r *Resource) GetStateAttrPath(path ...string) (cty.Value, bool) {
+	if r == nil || r.Item == nil {
+		return cty.NilVal, false
+	}
+	vals := WalkAndGet(strings.Join(path, "."), r.Item)
+	if len(vals) != 1 {
+		return cty.NilVal, false
+	}
+	return nativeToCty(vals[0]), true
+}
+
+// SensitiveValuePlaceholder replaces a sensitive attribute's real value in generated
+// output; it's a plain string rather than an HCL comment since it has to survive being
+// written as a normal attribute value.
+const SensitiveValuePlaceholder = "(sensitive value omitted)"
+
+// MarkSensitive records dotted paths (GetStateAttr syntax) as holding secrets, so
+// RedactSensitiveAttrs replaces them before the resource is written out. Duplicate
+// paths are ignored.
+func (r *Resource) MarkSensitive(paths ...string) {
+	for _, path := range paths {
+		found := false
+		for _, existing := range r.SensitiveAttrs {
+			if existing == path {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.SensitiveAttrs = append(r.SensitiveAttrs, path)
+		}
+	}
+}
+
+// RedactSensitiveAttrs overwrites every path in SensitiveAttrs with
+// SensitiveValuePlaceholder. Paths that don't currently resolve to anything are
+// skipped rather than creating a new attribute.
+func (r *Resource) RedactSensitiveAttrs() {
+	for _, path := range r.SensitiveAttrs {
+		if _, ok := r.GetStateAttr(path); !ok {
+			continue
+		}
+		r.SetStateAttrPath(cty.StringVal(SensitiveValuePlaceholder), strings.Split(path, ".")...)
+	}
+}
+
+// SetStateAttrPath writes value at an arbitrary-depth path into the resource's parsed
+// state, creating intermediate objects as needed and indexing into existing lists at
+// numeric-looking segments.
+func (r *Resource) SetStateAttrPath(value cty.Value, path ...string) {
+	if len(path) == 0 {
+		return
+	}
+	if r.Item == nil {
+		r.Item = map[string]interface{}{}
+	}
+	setNestedAttr(r.Item, path, ctyToNative(value))
+}
+
+// SetStateAttrTyped sets attr to goValue, formatted to match the kind (number, bool, or
+// plain string) that attr's current value already parses as, instead of whatever
+// formatting the caller happens to produce. This matters when a caller only has an
+// untyped Go value on hand (e.g. an int decoded from JSON as float64) and wants to write
+// it back without silently turning a numeric attribute into something GetStateAttrInt
+// can no longer parse. Returns an error if attr doesn't already exist, or if goValue
+// can't be converted to attr's kind.
+func (r *Resource) SetStateAttrTyped(attr string, goValue interface{}) error {
+	path := strings.Split(attr, ".")
+	raw, ok := lookupRawAttr(r.Item, path)
+	if !ok {
+		return fmt.Errorf("SetStateAttrTyped: %q does not exist", attr)
+	}
+	current, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("SetStateAttrTyped: %q is not a scalar attribute", attr)
+	}
+	ty := cty.String
+	switch {
+	case current == "":
+		// keep ty == cty.String
+	case isBoolString(current):
+		ty = cty.Bool
+	case isNumberString(current):
+		ty = cty.Number
+	}
+
+	implied, err := gocty.ImpliedType(goValue)
+	if err != nil {
+		return fmt.Errorf("SetStateAttrTyped: %q: %s", attr, err)
+	}
+	value, err := gocty.ToCtyValue(goValue, implied)
+	if err != nil {
+		return fmt.Errorf("SetStateAttrTyped: %q: %s", attr, err)
+	}
+	converted, err := convert.Convert(value, ty)
+	if err != nil {
+		return fmt.Errorf("SetStateAttrTyped: %q: cannot convert %s to %s: %s", attr, implied.FriendlyName(), ty.FriendlyName(), err)
+	}
+	r.SetStateAttrPath(converted, path...)
+	return nil
+}
+
+func isBoolString(s string) bool {
+	_, err := strconv.ParseBool(s)
+	return err == nil
+}
+
+func isNumberString(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func setNestedAttr(container map[string]interface{}, path []string, value interface{}) {
+	key := path[0]
+	if len(path) == 1 {
+		container[key] = value
+		return
+	}
+	rest := path[1:]
+	if index, err := strconv.Atoi(rest[0]); err == nil {
+		list, ok := container[key].([]interface{})
+		if !ok || index < 0 || index >= len(list) {
+			return
+		}
+		if len(rest) == 1 {
+			list[index] = value
+			return
+		}
+		sub, ok := list[index].(map[string]interface{})
+		if !ok {
+			sub = map[string]interface{}{}
+			list[index] = sub
+		}
+		setNestedAttr(sub, rest[1:], value)
+		return
+	}
+	sub, ok := container[key].(map[string]interface{})
+	if !ok {
+		sub = map[string]interface{}{}
+		container[key] = sub
+	}
+	setNestedAttr(sub, rest, value)
+}
+
+// GetStateAttrSlice returns the elements of a list/set-valued attribute as cty.Value,
+// in their current order. The second return reports whether the attribute exists and
+// is a list.
+func (r *Resource) GetStateAttrSlice(attr string) ([]cty.Value, bool) {
+	raw, ok := lookupRawAttr(r.Item, strings.Split(attr, "."))
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	vals := make([]cty.Value, len(list))
+	for i, e := range list {
+		vals[i] = nativeToCty(e)
+	}
+	return vals, true
+}
+
+// GetStateAttrSliceStrings is GetStateAttrSlice for the common case of a string list:
+// it returns the value of each string element, silently skipping any element that
+// isn't a string (or is null), so a PostConvertHook can write
+// `for _, s := range r.GetStateAttrSliceStrings("services")` instead of unwrapping
+// cty.Value itself.
+func (r *Resource) GetStateAttrSliceStrings(attr string) []string {
+	vals, ok := r.GetStateAttrSlice(attr)
+	if !ok {
+		return nil
+	}
+	strs := make([]string, 0, len(vals))
+	for _, v := range vals {
+		if v.IsNull() || v.Type() != cty.String {
+			continue
+		}
+		strs = append(strs, v.AsString())
+	}
+	return strs
+}
+
+// lookupRawAttr walks a dotted path of map keys, without flattening across lists the
+// way WalkAndGet does, so the caller can inspect the raw value found at that path.
+func lookupRawAttr(container interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return container, true
+	}
+	m, ok := container.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	v, ok := m[path[0]]
+	if !ok {
+		return nil, false
+	}
+	return lookupRawAttr(v, path[1:])
+}
+
+// ListToValue wraps a slice of cty.Value back into a single cty.Value list, suitable
+// for SetStateAttrPath. It builds a cty.Tuple rather than a cty.List, so elements of
+// differing types (e.g. a provider response mixing strings and objects across list
+// entries) are preserved as-is instead of going through convert.Unify, which would
+// otherwise fail an entire import over one malformed element.
+func ListToValue(vals []cty.Value) cty.Value {
+	if len(vals) == 0 {
+		return cty.EmptyTupleVal
+	}
+	return cty.TupleVal(vals)
+}
+
+// AppendToStateAttrSlice appends value to attr's list, creating a single-element list
+// if attr doesn't already exist (or isn't a list). This is the common case of a
+// generator wanting to grow a list attribute without first checking whether it's there,
+// e.g. a PostConvertHook building up "services" one entry at a time.
+func (r *Resource) AppendToStateAttrSlice(attr string, value cty.Value) {
+	vals, _ := r.GetStateAttrSlice(attr)
+	vals = append(vals, value)
+	r.SetStateAttrPath(ListToValue(vals), attr)
+}
+
+// SortStateAttrStringSlice sorts a string-valued list attribute lexically in place.
+func (r *Resource) SortStateAttrStringSlice(attr string) {
+	vals, ok := r.GetStateAttrSlice(attr)
+	if !ok {
+		return
+	}
+	sort.SliceStable(vals, func(i, j int) bool {
+		return vals[i].AsString() < vals[j].AsString()
+	})
+	r.SetStateAttrPath(ListToValue(vals), attr)
+}
+
+// SortStateAttrNumberSlice sorts a numeric list attribute by value in place, so that
+// e.g. [10, 100, 2] becomes [2, 10, 100] instead of sorting lexically as strings. This
+// keeps generated files stable across runs for providers that expose unordered numeric
+// lists such as ports.
+func (r *Resource) SortStateAttrNumberSlice(attr string) {
+	vals, ok := r.GetStateAttrSlice(attr)
+	if !ok {
+		return
+	}
+	sort.SliceStable(vals, func(i, j int) bool {
+		iVal, _ := strconv.ParseFloat(valueToString(vals[i]), 64)
+		jVal, _ := strconv.ParseFloat(valueToString(vals[j]), 64)
+		return iVal < jVal
+	})
+	r.SetStateAttrPath(ListToValue(vals), attr)
+}
+
+// GetStateAttrMap returns a map-valued attribute as cty.Value along with its keys in
+// sorted order. The second return reports whether the attribute exists and is a map.
+func (r *Resource) GetStateAttrMap(attr string) (map[string]cty.Value, []string, bool) {
+	raw, ok := lookupRawAttr(r.Item, strings.Split(attr, "."))
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, nil, false
+	}
+	vals := make(map[string]cty.Value, len(m))
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		vals[k] = nativeToCty(v)
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return vals, keys, true
+}
+
+// CanonicalizeStateAttrMap rewrites a map-valued attribute using a sorted key order.
+// Go maps have no stored iteration order of their own, so this doesn't change what
+// Resource.Item holds; it matters because the final state/HCL output is produced by
+// json.Marshal-ing Item (see hcl.go), which already sorts map keys alphabetically. This
+// helper exists so callers that read a map attribute directly, rather than through the
+// JSON output path, get the same deterministic ordering.
+func (r *Resource) CanonicalizeStateAttrMap(attr string) {
+	vals, keys, ok := r.GetStateAttrMap(attr)
+	if !ok {
+		return
+	}
+	ordered := map[string]interface{}{}
+	for _, k := range keys {
+		ordered[k] = ctyToNative(vals[k])
+	}
+	setNestedAttr(r.Item, strings.Split(attr, "."), ordered)
+}
+
+// ValueRewriter normalizes a single attribute value before output, e.g. pretty-printing
+// a JSON policy or decoding a base64 blob. path is the attribute's dotted location in
+// Resource.Item (list elements are numeric segments, matching GetStateAttrPath/
+// SetStateAttrPath); the returned value replaces it.
+type ValueRewriter func(path string, val cty.Value) cty.Value
+
+// RewriteValues applies rewriter to every leaf value in the resource's parsed state,
+// replacing Item in place. It's a no-op if rewriter or Item is nil.
+func (r *Resource) RewriteValues(rewriter ValueRewriter) {
+	if rewriter == nil || r.Item == nil {
+		return
+	}
+	r.Item = rewriteValue("", r.Item, rewriter).(map[string]interface{})
+}
+
+func rewriteValue(path string, val interface{}, rewriter ValueRewriter) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = rewriteValue(joinPath(path, k), e, rewriter)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, e := range v {
+			out[i] = rewriteValue(joinPath(path, strconv.Itoa(i)), e, rewriter)
+		}
+		return out
+	default:
+		return ctyToNative(rewriter(path, nativeToCty(val)))
+	}
+}
+
+func joinPath(path, segment string) string {
+	if path == "" {
+		return segment
+	}
+	return path + "." + segment
+}
+
+// nativeToCty converts the plain Go values found in Resource.Item (strings, and nested
+// []interface{}/map[string]interface{}) into their cty.Value equivalent.
+func nativeToCty(val interface{}) cty.Value {
+	switch v := val.(type) {
+	case string:
+		return cty.StringVal(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return cty.EmptyTupleVal
+		}
+		vals := make([]cty.Value, len(v))
+		for i, e := range v {
+			vals[i] = nativeToCty(e)
+		}
+		return cty.TupleVal(vals)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return cty.EmptyObjectVal
+		}
+		vals := make(map[string]cty.Value, len(v))
+		for k, e := range v {
+			vals[k] = nativeToCty(e)
+		}
+		return cty.ObjectVal(vals)
+	default:
+		return cty.NilVal
+	}
+}
+
+// ctyToNative is the inverse of nativeToCty, converting a cty.Value back into the
+// plain Go representation used by Resource.Item.
+func ctyToNative(val cty.Value) interface{} {
+	if val.IsNull() || !val.IsKnown() {
+		return nil
+	}
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return val.AsString()
+	case ty == cty.Bool:
+		return strconv.FormatBool(val.True())
+	case ty == cty.Number:
+		return val.AsBigFloat().Text('f', -1)
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		// Start from an empty (not nil) slice, so an empty list keeps its list identity
+		// through JSON marshaling (`[]`) instead of collapsing to `null`, which several
+		// providers' schemas reject for a typed list attribute.
+		items := []interface{}{}
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			items = append(items, ctyToNative(elem))
+		}
+		return items
+	case ty.IsMapType(), ty.IsObjectType():
+		items := map[string]interface{}{}
+		for it := val.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			items[key.AsString()] = ctyToNative(elem)
+		}
+		return items
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+package terraform_utils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestGetStateAttrBool(t *testing.T) {
+	r := Resource{Item: mapI("enabled", "true")}
+
+	value, ok := r.GetStateAttrBool("enabled")
+	if !ok || !value {
+		t.Errorf("expected enabled=true, got %v, %v", value, ok)
+	}
+
+	if _, ok := r.GetStateAttrBool("missing"); ok {
+		t.Errorf("expected missing attribute to report false")
+	}
+}
+
+func TestGetStateAttrInt(t *testing.T) {
+	r := Resource{Item: mapI("size", "100")}
+
+	value, ok := r.GetStateAttrInt("size")
+	if !ok || value != 100 {
+		t.Errorf("expected size=100, got %v, %v", value, ok)
+	}
+
+	if _, ok := r.GetStateAttrInt("missing"); ok {
+		t.Errorf("expected missing attribute to report false")
+	}
+}
+
+func TestGetStateAttrPath(t *testing.T) {
+	r := Resource{Item: mapI("nested", []interface{}{
+		mapI("name", "first"),
+		mapI("name", "second")})}
+
+	value, ok := r.GetStateAttrPath("nested", "1", "name")
+	if !ok || value.AsString() != "second" {
+		t.Errorf("expected nested.1.name=second, got %v, %v", value, ok)
+	}
+}
+
+func TestHasStateAttrNilSafe(t *testing.T) {
+	var nilResource *Resource
+	if nilResource.HasStateAttr("services") {
+		t.Error("expected a nil *Resource to report false rather than panic")
+	}
+
+	empty := &Resource{}
+	if empty.HasStateAttr("services") {
+		t.Error("expected a resource with no Item (e.g. a failed-refresh resource never reaching ConvertTFstate) to report false rather than panic")
+	}
+
+	r := &Resource{Item: mapI("services", []interface{}{"lambda"})}
+	if !r.HasStateAttr("services") {
+		t.Error("expected services to be reported present")
+	}
+}
+
+func TestAppendToStateAttrSliceCreatesMissingList(t *testing.T) {
+	r := &Resource{}
+
+	r.AppendToStateAttrSlice("services", cty.StringVal("lambda"))
+
+	vals, ok := r.GetStateAttrSlice("services")
+	if !ok || len(vals) != 1 || vals[0].AsString() != "lambda" {
+		t.Fatalf("expected services=[lambda], got %v, %v", vals, ok)
+	}
+}
+
+func TestAppendToStateAttrSliceAppendsToExisting(t *testing.T) {
+	r := &Resource{Item: mapI("services", []interface{}{"lambda"})}
+
+	r.AppendToStateAttrSlice("services", cty.StringVal("cloudtrail"))
+
+	vals, ok := r.GetStateAttrSlice("services")
+	if !ok || len(vals) != 2 || vals[0].AsString() != "lambda" || vals[1].AsString() != "cloudtrail" {
+		t.Fatalf("expected services=[lambda, cloudtrail], got %v, %v", vals, ok)
+	}
+}
+
+func TestSortStateAttrNumberSlice(t *testing.T) {
+	r := Resource{Item: mapI("ports", []interface{}{"10", "100", "2"})}
+
+	r.SortStateAttrNumberSlice("ports")
+
+	vals, ok := r.GetStateAttrSlice("ports")
+	if !ok || len(vals) != 3 {
+		t.Fatalf("expected 3 sorted ports, got %v, %v", vals, ok)
+	}
+	got := []string{vals[0].AsString(), vals[1].AsString(), vals[2].AsString()}
+	want := []string{"2", "10", "100"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected numeric sort %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestGetStateAttrSliceStrings(t *testing.T) {
+	r := Resource{Item: mapI("services", []interface{}{"ec2", "s3", nil, "5"})}
+
+	got := r.GetStateAttrSliceStrings("services")
+	want := []string{"ec2", "s3", "5"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			break
+		}
+	}
+
+	empty := Resource{Item: map[string]interface{}{}}
+	if got := empty.GetStateAttrSliceStrings("missing"); got != nil {
+		t.Errorf("expected nil for a missing attribute, got %v", got)
+	}
+}
+
+func TestSetStateAttrTyped(t *testing.T) {
+	r := Resource{Item: mapI("size", "100")}
+
+	if err := r.SetStateAttrTyped("size", float64(200)); err != nil {
+		t.Fatalf("SetStateAttrTyped failed: %s", err)
+	}
+	value, ok := r.GetStateAttrInt("size")
+	if !ok || value != 200 {
+		t.Errorf("expected size=200, got %v, %v", value, ok)
+	}
+
+	if err := r.SetStateAttrTyped("size", "not a number"); err == nil {
+		t.Errorf("expected an error converting a non-numeric string into a numeric attribute")
+	}
+
+	if err := r.SetStateAttrTyped("missing", "value"); err == nil {
+		t.Errorf("expected an error setting a missing attribute")
+	}
+}
+
+func TestListToValueToleratesMixedElementTypes(t *testing.T) {
+	vals := []cty.Value{cty.StringVal("a"), cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("b")})}
+
+	value := ListToValue(vals)
+
+	if !value.Type().IsTupleType() {
+		t.Fatalf("expected a tuple type, got %s", value.Type().FriendlyName())
+	}
+	if value.LengthInt() != 2 {
+		t.Errorf("expected 2 elements, got %d", value.LengthInt())
+	}
+}
+
+func TestSetStateAttrPathEmptyListStaysAList(t *testing.T) {
+	r := Resource{Item: mapI("services", []interface{}{"ec2"})}
+
+	r.SetStateAttrPath(ListToValue(nil), "services")
+
+	value, ok := r.Item["services"].([]interface{})
+	if !ok {
+		t.Fatalf("expected services to remain a slice, got %T: %v", r.Item["services"], r.Item["services"])
+	}
+	if len(value) != 0 {
+		t.Errorf("expected an empty slice, got %v", value)
+	}
+	encoded, err := json.Marshal(r.Item)
+	if err != nil {
+		t.Fatalf("json.Marshal failed: %s", err)
+	}
+	if !strings.Contains(string(encoded), `"services":[]`) {
+		t.Errorf("expected services to encode as [], got %s", encoded)
+	}
+}
+
+func TestRewriteValues(t *testing.T) {
+	r := Resource{Item: mapI("tags", map[string]interface{}{"Name": "web"})}
+
+	r.RewriteValues(func(path string, val cty.Value) cty.Value {
+		if path == "tags.Name" {
+			return cty.StringVal(strings.ToUpper(val.AsString()))
+		}
+		return val
+	})
+
+	value, ok := r.GetStateAttrPath("tags", "Name")
+	if !ok || value.AsString() != "WEB" {
+		t.Errorf("expected tags.Name=WEB, got %v, %v", value, ok)
+	}
+}
+
+func TestCanonicalizeStateAttrMap(t *testing.T) {
+	r := Resource{Item: mapI("tags", map[string]interface{}{"Zone": "us-east-1", "App": "web"})}
+
+	r.CanonicalizeStateAttrMap("tags")
+
+	_, keys, ok := r.GetStateAttrMap("tags")
+	if !ok || len(keys) != 2 {
+		t.Fatalf("expected 2 sorted keys, got %v, %v", keys, ok)
+	}
+	if keys[0] != "App" || keys[1] != "Zone" {
+		t.Errorf("expected sorted keys [App Zone], got %v", keys)
+	}
+}
+
+func TestSetStateAttrPath(t *testing.T) {
+	r := Resource{Item: mapI("nested", []interface{}{
+		mapI("name", "first")})}
+
+	r.SetStateAttrPath(cty.StringVal("updated"), "nested", "0", "name")
+
+	value, ok := r.GetStateAttrPath("nested", "0", "name")
+	if !ok || value.AsString() != "updated" {
+		t.Errorf("expected nested.0.name=updated, got %v, %v", value, ok)
+	}
+}
+
+func TestRedactSensitiveAttrs(t *testing.T) {
+	r := Resource{Item: mapI("password", "hunter2")}
+	r.MarkSensitive("password")
+
+	r.RedactSensitiveAttrs()
+
+	value, ok := r.GetStateAttr("password")
+	if !ok || value != SensitiveValuePlaceholder {
+		t.Errorf("expected password to be redacted, got %v, %v", value, ok)
+	}
+}
+
+func TestRedactSensitiveAttrsSkipsMissingPaths(t *testing.T) {
+	r := Resource{Item: mapI("name", "test")}
+	r.MarkSensitive("password")
+
+	r.RedactSensitiveAttrs()
+
+	if _, ok := r.GetStateAttr("password"); ok {
+		t.Errorf("expected no password attribute to be created")
+	}
+}
+
+func TestMarkSensitiveDeduplicates(t *testing.T) {
+	r := Resource{}
+	r.MarkSensitive("password")
+	r.MarkSensitive("password")
+
+	if len(r.SensitiveAttrs) != 1 {
+		t.Errorf("expected 1 sensitive attr, got %v", r.SensitiveAttrs)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraform_output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+// WriteDataFiles writes each resource's Resource.DataFiles to disk under path, at the
+// path Resource.DataFilePath reports for that entry, gzip-compressing content added
+// with compress=true. A generator that populated DataFiles is expected to have already
+// pointed the relevant attribute at DataFilePath in its PostConvertHook, so this only
+// needs to make that path exist.
+func WriteDataFiles(resources []terraform_utils.Resource, path string) error {
+	for _, r := range resources {
+		for name, df := range r.DataFiles {
+			content := df.Content
+			if df.Compress {
+				var buf bytes.Buffer
+				gzWriter := gzip.NewWriter(&buf)
+				if _, err := gzWriter.Write(content); err != nil {
+					return err
+				}
+				if err := gzWriter.Close(); err != nil {
+					return err
+				}
+				content = buf.Bytes()
+			}
+			file := filepath.Join(path, r.DataFilePath(name))
+			if err := os.MkdirAll(filepath.Dir(file), os.ModePerm); err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(file, content, os.ModePerm); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//      http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -68,6 +68,16 @@ func OutputHclFiles(resources []terraform_utils.Resource, provider terraform_uti
 				}
 			}
 		}
+		for _, req := range r.RequestedOutputs {
+			key := r.InstanceInfo.Type + "_" + r.ResourceName + "_" + req.Name
+			outputsByResource[key] = map[string]interface{}{
+				"value": "${" + r.InstanceInfo.Type + "." + r.ResourceName + "." + req.Attr + "}",
+			}
+			outputState[key] = &terraform.OutputState{
+				Type:  "string",
+				Value: r.InstanceState.Attributes[req.Attr],
+			}
+		}
 		resources[i].Outputs = outputState
 	}
 	if len(outputsByResource) > 0 {
@@ -84,6 +94,9 @@ func OutputHclFiles(resources []terraform_utils.Resource, provider terraform_uti
 	for _, r := range resources {
 		typeOfServices[r.InstanceInfo.Type] = append(typeOfServices[r.InstanceInfo.Type], r)
 	}
+	if err := cleanStaleResourceFiles(path, typeOfServices, isCompact); err != nil {
+		return err
+	}
 	if isCompact {
 		err := printFile(resources, "resources", path)
 		if err != nil {
@@ -101,6 +114,54 @@ func OutputHclFiles(resources []terraform_utils.Resource, provider terraform_uti
 	return nil
 }
 
+// cleanStaleResourceFiles removes whichever set of resource files the current run won't
+// regenerate, so switching --compact on or off between runs against the same output
+// directory doesn't leave a stale resources.tf (or stale per-type file) behind with
+// resource blocks Terraform would then see defined twice.
+func cleanStaleResourceFiles(path string, typeOfServices map[string][]terraform_utils.Resource, isCompact bool) error {
+	if isCompact {
+		for k := range typeOfServices {
+			fileName := strings.Replace(k, strings.Split(k, "_")[0]+"_", "", -1)
+			if err := removeIfExists(path + "/" + fileName + ".tf"); err != nil {
+				return err
+			}
+		}
+	} else {
+		if err := removeIfExists(path + "/resources.tf"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeIfExists(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// OutputRootModule writes a root-level main.tf with a module block per entry in
+// moduleSources (service name -> relative source path), so `terraform init` at path
+// picks up every service directory OutputHclFiles already wrote as a child module.
+func OutputRootModule(path string, moduleSources map[string]string) error {
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return err
+	}
+	modules := map[string]interface{}{}
+	for serviceName, source := range moduleSources {
+		modules[serviceName] = map[string]interface{}{"source": source}
+	}
+	data := map[string]interface{}{"module": modules}
+	mainFile, err := terraform_utils.HclPrint(data, map[string]struct{}{})
+	if err != nil {
+		return err
+	}
+	PrintFile(path+"/main.tf", mainFile)
+	return nil
+}
+
 func printFile(v []terraform_utils.Resource, fileName string, path string) error {
 	tfFile, err := terraform_utils.HclPrintResource(v, map[string]interface{}{})
 	if err != nil {
@@ -0,0 +1,37 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraform_output
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+// OutputImportBlocks writes path/imports.tf with a Terraform 1.5+ `import` block per
+// resource, so Terraform itself performs the import on the next plan/apply instead of
+// terraformer writing a terraform.tfstate directly. `to` must be a bare resource
+// reference rather than a quoted string, which is why this is hand-formatted here
+// instead of going through HclPrint: HclPrint's HCL1-based pipeline (see hcl.go) can
+// only emit bare identifiers via a text-level pass after formatting, and an import
+// block's shape is fixed and simple enough not to need that machinery at all.
+func OutputImportBlocks(resources []terraform_utils.Resource, path string) error {
+	var b strings.Builder
+	for _, r := range resources {
+		fmt.Fprintf(&b, "import {\n  to = %s\n  id = %q\n}\n\n", r.Address(), r.FormattedImportID())
+	}
+	return ioutil.WriteFile(path+"/imports.tf", []byte(b.String()), 0644)
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraform_output
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+// OutputImportScript writes path/import.sh, one `terraform import <address> <id>` per
+// resource, for users who'd rather review the generated HCL and run the import
+// themselves than trust the generated state file. FormattedImportID already accounts
+// for a generator-supplied composite import ID, so this needs no special-casing.
+func OutputImportScript(resources []terraform_utils.Resource, path string) error {
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\nset -euo pipefail\n\n")
+	for _, r := range resources {
+		fmt.Fprintf(&b, "terraform import %s %s\n", r.Address(), shellQuote(r.FormattedImportID()))
+	}
+	return ioutil.WriteFile(path+"/import.sh", []byte(b.String()), 0755)
+}
+
+// shellQuote wraps s in single quotes for safe use as a shell word, escaping any single
+// quotes it already contains. Import IDs are usually plain, but some providers embed
+// slashes or colons that would otherwise need the reader to guess at quoting.
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
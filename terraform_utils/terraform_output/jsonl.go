@@ -0,0 +1,42 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraform_output
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+)
+
+// OutputResourceInventory writes one JSON object per line to path, one per resource,
+// for tooling integrations that want a machine-readable inventory of everything
+// terraformer imported. Each line is a full terraform_utils.Resource, the same shape
+// already used in plan.json, so it round-trips with encoding/json without a separate
+// distilled schema to keep in sync.
+func OutputResourceInventory(resources []terraform_utils.Resource, path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, r := range resources {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 The Terraformer Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package terraform_output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/terraformer/terraform_utils"
+	"gopkg.in/yaml.v2"
+)
+
+// OutputKubernetesManifests writes each resource's imported attributes as a YAML file
+// under path/manifests, alongside the HCL terraformer already writes. Files are
+// organized by namespace, then by Terraform resource type (e.g. kubernetes_deployment)
+// -- the Kubernetes Kind itself doesn't survive the refresh/convert pipeline, only the
+// resource type the generator chose in extractTfResourceName, so that's what's used
+// here too. Cluster-scoped resources, whose ImportID has no "namespace/" prefix, land
+// directly under manifests/.
+func OutputKubernetesManifests(resources []terraform_utils.Resource, path string) error {
+	manifestsPath := filepath.Join(path, "manifests")
+	for _, r := range resources {
+		namespace, name := splitNamespacedID(r.ImportID())
+		dir := manifestsPath
+		if namespace != "" {
+			dir = filepath.Join(manifestsPath, namespace)
+		}
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+		encoded, err := yaml.Marshal(r.Item)
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(dir, r.InstanceInfo.Type+"_"+terraform_utils.TfSanitize(name)+".yaml")
+		if err := ioutil.WriteFile(file, encoded, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitNamespacedID splits a Kind ImportID of the form "namespace/name" into its two
+// parts, or returns ("", id) unchanged for a cluster-scoped resource's bare name.
+func splitNamespacedID(id string) (namespace, name string) {
+	if idx := strings.Index(id, "/"); idx != -1 {
+		return id[:idx], id[idx+1:]
+	}
+	return "", id
+}
@@ -16,11 +16,17 @@ package terraform_utils
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"hash/crc32"
 	"log"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/GoogleCloudPlatform/terraformer/terraform_utils/provider_wrapper"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/terraform/terraform"
 
 	"github.com/zclconf/go-cty/cty"
@@ -67,12 +73,59 @@ func PrintTfState(resources []Resource) ([]byte, error) {
 	return buf.Bytes(), err
 }
 
-func RefreshResources(resources []Resource, provider *provider_wrapper.ProviderWrapper) ([]Resource, error) {
+// MergeTfState adds resources to an existing state read with terraform.ReadState,
+// instead of replacing it outright. A resource whose address already exists in
+// existing is left untouched and its address is returned in conflicts, rather than
+// silently overwriting state a previous run (or a manual terraform import) produced.
+func MergeTfState(existing *terraform.State, resources []Resource) (conflicts []string, err error) {
+	if len(existing.Modules) == 0 {
+		existing.Modules = []*terraform.ModuleState{{Path: []string{"root"}}}
+	}
+	root := existing.Modules[0]
+	if root.Resources == nil {
+		root.Resources = map[string]*terraform.ResourceState{}
+	}
+	if root.Outputs == nil {
+		root.Outputs = map[string]*terraform.OutputState{}
+	}
+	for _, r := range resources {
+		address := r.InstanceInfo.Type + "." + r.ResourceName
+		if _, exists := root.Resources[address]; exists {
+			conflicts = append(conflicts, address)
+			continue
+		}
+		root.Resources[address] = &terraform.ResourceState{
+			Type:     r.InstanceInfo.Type,
+			Primary:  r.InstanceState,
+			Provider: "provider." + r.Provider,
+		}
+		for k, v := range r.Outputs {
+			root.Outputs[k] = v
+		}
+	}
+	existing.Serial++
+	return conflicts, nil
+}
+
+// DefaultRefreshParallelism is used by callers that don't need to tune the worker pool
+// size themselves.
+const DefaultRefreshParallelism = 15
+
+// RefreshResources refreshes resources concurrently across a bounded worker pool.
+// Cancelling ctx (e.g. on Ctrl-C) stops resources that haven't started their provider
+// RPC yet from being refreshed; see Resource.Refresh for what cancellation can and
+// can't interrupt.
+func RefreshResources(ctx context.Context, resources []Resource, provider *provider_wrapper.ProviderWrapper, parallelism int) ([]Resource, error) {
+	if parallelism <= 0 {
+		parallelism = DefaultRefreshParallelism
+	}
 	refreshedResources := []Resource{}
 	input := make(chan *Resource, 100)
 	var wg sync.WaitGroup
-	for i := 0; i < 15; i++ {
-		go RefreshResourceWorker(input, &wg, provider)
+	var errs error
+	var errsMutex sync.Mutex
+	for i := 0; i < parallelism; i++ {
+		go RefreshResourceWorker(ctx, input, &wg, provider, &errs, &errsMutex)
 	}
 	for i := range resources {
 		wg.Add(1)
@@ -87,13 +140,21 @@ func RefreshResources(resources []Resource, provider *provider_wrapper.ProviderW
 			log.Printf("ERROR: Unable to refresh resource %s", r.ResourceName)
 		}
 	}
-	return refreshedResources, nil
+	return refreshedResources, errs
 }
 
-func RefreshResourceWorker(input chan *Resource, wg *sync.WaitGroup, provider *provider_wrapper.ProviderWrapper) {
+func RefreshResourceWorker(ctx context.Context, input chan *Resource, wg *sync.WaitGroup, provider *provider_wrapper.ProviderWrapper, errs *error, errsMutex *sync.Mutex) {
 	for r := range input {
+		if ctx.Err() != nil {
+			wg.Done()
+			continue
+		}
 		log.Println("Refreshing state...", r.InstanceInfo.Id)
-		r.Refresh(provider)
+		if err := r.Refresh(ctx, provider); err != nil {
+			errsMutex.Lock()
+			*errs = multierror.Append(*errs, err)
+			errsMutex.Unlock()
+		}
 		wg.Done()
 	}
 }
@@ -113,6 +174,38 @@ func IgnoreKeys(resourcesTypes []string, providerName string, providerConfig cty
 	return readOnlyAttributes
 }
 
+// MissingRequiredAttributes checks each resource's converted Item against the
+// provider schema's required top-level attributes and returns a human-readable
+// message for every resource missing one, so a bad AllowEmptyValues/IgnoreKeys
+// pattern shows up as an actionable list at import time instead of a cryptic
+// `terraform plan` failure later.
+func MissingRequiredAttributes(resources []Resource, provider *provider_wrapper.ProviderWrapper) ([]string, error) {
+	resourceTypes := map[string]bool{}
+	for _, r := range resources {
+		resourceTypes[r.InstanceInfo.Type] = true
+	}
+	types := make([]string, 0, len(resourceTypes))
+	for t := range resourceTypes {
+		types = append(types, t)
+	}
+
+	required, err := provider.GetRequiredAttributes(types)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, r := range resources {
+		for _, attr := range required[r.InstanceInfo.Type] {
+			value, exists := r.Item[attr]
+			if !exists || value == nil || value == "" {
+				problems = append(problems, fmt.Sprintf("%s.%s is missing required attribute %q", r.InstanceInfo.Type, r.ResourceName, attr))
+			}
+		}
+	}
+	return problems, nil
+}
+
 func ParseFilterValues(value string) []string {
 	var values []string
 
@@ -150,9 +243,10 @@ func FilterCleanup(s *Service, isInitial bool) {
 	var newListOfResources []Resource
 	for _, resource := range s.Resources {
 		allPredicatesTrue := true
-		for _, filter := range s.Filter {
-			if filter.isInitial() == isInitial {
-				allPredicatesTrue = allPredicatesTrue && filter.Filter(resource)
+		for i := range s.Filter {
+			group := s.Filter[i]
+			if group.isInitial() == isInitial {
+				allPredicatesTrue = allPredicatesTrue && group.Filter(resource)
 			}
 		}
 		if allPredicatesTrue && !ContainsResource(newListOfResources, resource) {
@@ -162,6 +256,223 @@ func FilterCleanup(s *Service, isInitial bool) {
 	s.Resources = newListOfResources
 }
 
+// DedupeResources collapses resources that share the same Provider, InstanceInfo.Type,
+// and InstanceState.ID, keeping the first occurrence and merging any AdditionalFields
+// present on later duplicates but missing from the one that's kept. This fixes
+// generators that accidentally emit the same resource twice, e.g. when it's returned
+// by two paginated API calls, which would otherwise produce two Terraform blocks with
+// the same import ID and fail `terraform plan`.
+func DedupeResources(resources []Resource) []Resource {
+	var deduped []Resource
+	seen := map[string]int{}
+	for _, resource := range resources {
+		key := resource.Provider + "/" + resource.InstanceInfo.Type + "/" + resource.InstanceState.ID
+		if i, ok := seen[key]; ok {
+			for field, value := range resource.AdditionalFields {
+				if _, exists := deduped[i].AdditionalFields[field]; !exists {
+					if deduped[i].AdditionalFields == nil {
+						deduped[i].AdditionalFields = map[string]interface{}{}
+					}
+					deduped[i].AdditionalFields[field] = value
+				}
+			}
+			continue
+		}
+		seen[key] = len(deduped)
+		deduped = append(deduped, resource)
+	}
+	return deduped
+}
+
+// RenameDuplicateResources scans resources for ResourceName collisions within the same
+// InstanceInfo.Type (which TfSanitize can produce for two different IDs that sanitize
+// to the same name) and appends a short suffix derived from InstanceState.ID to every
+// name after the first, leaving already-unique resources untouched. The suffix is
+// deterministic, so re-running the same import produces the same disambiguated names.
+func RenameDuplicateResources(resources []Resource) {
+	seen := map[string]bool{}
+	for i := range resources {
+		r := &resources[i]
+		key := r.InstanceInfo.Type + "." + r.ResourceName
+		if !seen[key] {
+			seen[key] = true
+			continue
+		}
+		suffix := fmt.Sprintf("%x", crc32.ChecksumIEEE([]byte(r.InstanceState.ID)))
+		if len(suffix) > 6 {
+			suffix = suffix[:6]
+		}
+		newName := r.ResourceName + "-" + suffix
+		newKey := r.InstanceInfo.Type + "." + newName
+		for seen[newKey] {
+			suffix += "0"
+			newName = r.ResourceName + "-" + suffix
+			newKey = r.InstanceInfo.Type + "." + newName
+		}
+		r.ResourceName = newName
+		r.InstanceInfo.Id = fmt.Sprintf("%s.%s", r.InstanceInfo.Type, newName)
+		seen[newKey] = true
+	}
+}
+
+// MaxCollapseForEachVaryingKeys bounds how many attributes CollapseForEach will accept
+// as the varying set before giving up on a group: past a handful, the collapsed
+// resource's locals map is no easier to read than the flat resources it replaced.
+const MaxCollapseForEachVaryingKeys = 3
+
+// CollapseForEach is an opt-in pass that looks for resources of the same
+// InstanceInfo.Type whose Item is identical except for a small set of keys, and
+// collapses each such group into one resource driven by for_each plus a locals map
+// entry, the way a human writing this config by hand would rather than repeating
+// near-identical blocks. It returns the rewritten resource list (collapsed groups
+// replaced by a single synthetic Resource each, singletons untouched) and a locals map
+// keyed by a name derived from the group's type; the caller is responsible for
+// rendering that map into a `locals` block alongside the resources.
+//
+// Two things keep this deliberately conservative:
+//   - A group is only collapsed if every member has exactly the same set of Item keys,
+//     and at most MaxCollapseForEachVaryingKeys of them differ across members.
+//   - A group is skipped entirely if any member's Item references another member's
+//     address (e.g. "${aws_iam_role.b.arn}" appearing inside aws_iam_role.a's Item),
+//     since for_each can't express one instance in a group depending on a sibling.
+//
+// The generated state for a collapsed resource (see PrintTfState) only reflects the
+// first member of its group; a caller combining this with local state output should
+// re-import the remaining instances (e.g. via CollapseForEach's locals keys) rather
+// than trust the written terraform.tfstate for them.
+func CollapseForEach(resources []Resource) (collapsed []Resource, locals map[string]interface{}) {
+	indexesByType := map[string][]int{}
+	for i, r := range resources {
+		indexesByType[r.InstanceInfo.Type] = append(indexesByType[r.InstanceInfo.Type], i)
+	}
+
+	locals = map[string]interface{}{}
+	skip := map[int]bool{}
+	var groups []Resource
+	for resourceType, indexes := range indexesByType {
+		if len(indexes) < 2 {
+			continue
+		}
+		varyingKeys, ok := collapseVaryingKeys(resources, indexes)
+		if !ok {
+			continue
+		}
+		if collapseGroupHasSiblingReferences(resources, indexes, resourceType) {
+			continue
+		}
+
+		base := resources[indexes[0]]
+		localsKey := strings.Replace(resourceType, strings.SplitN(resourceType, "_", 2)[0]+"_", "", 1)
+
+		item := make(map[string]interface{}, len(base.Item))
+		for k, v := range base.Item {
+			item[k] = v
+		}
+		for _, key := range varyingKeys {
+			item[key] = "${each.value." + key + "}"
+		}
+		item["for_each"] = "${local." + localsKey + "}"
+
+		instances := map[string]interface{}{}
+		for _, i := range indexes {
+			r := resources[i]
+			entry := map[string]interface{}{}
+			for _, key := range varyingKeys {
+				entry[key] = r.Item[key]
+			}
+			instances[r.ResourceName] = entry
+			skip[i] = true
+		}
+		locals[localsKey] = instances
+
+		groups = append(groups, Resource{
+			InstanceInfo:  base.InstanceInfo,
+			InstanceState: base.InstanceState,
+			ResourceName:  localsKey,
+			Provider:      base.Provider,
+			Item:          item,
+		})
+	}
+
+	for i, r := range resources {
+		if !skip[i] {
+			collapsed = append(collapsed, r)
+		}
+	}
+	collapsed = append(collapsed, groups...)
+	return collapsed, locals
+}
+
+// collapseVaryingKeys returns the Item keys that differ across resources[indexes], or
+// ok=false if the members don't share an identical key set or more than
+// MaxCollapseForEachVaryingKeys keys differ.
+func collapseVaryingKeys(resources []Resource, indexes []int) (varying []string, ok bool) {
+	base := resources[indexes[0]].Item
+	varyingSet := map[string]bool{}
+	for _, i := range indexes[1:] {
+		item := resources[i].Item
+		if len(item) != len(base) {
+			return nil, false
+		}
+		for k, baseValue := range base {
+			value, exists := item[k]
+			if !exists {
+				return nil, false
+			}
+			if fmt.Sprintf("%v", value) != fmt.Sprintf("%v", baseValue) {
+				varyingSet[k] = true
+			}
+		}
+	}
+	if len(varyingSet) == 0 || len(varyingSet) > MaxCollapseForEachVaryingKeys {
+		return nil, false
+	}
+	for k := range varyingSet {
+		varying = append(varying, k)
+	}
+	sort.Strings(varying)
+	return varying, true
+}
+
+// collapseGroupHasSiblingReferences reports whether any resource in the group has an
+// Item value referencing another member's address, which for_each can't express.
+func collapseGroupHasSiblingReferences(resources []Resource, indexes []int, resourceType string) bool {
+	var siblingRefs []string
+	for _, i := range indexes {
+		siblingRefs = append(siblingRefs, "${"+resourceType+"."+resources[i].ResourceName+".")
+	}
+	for _, i := range indexes {
+		if itemContainsAny(resources[i].Item, siblingRefs) {
+			return true
+		}
+	}
+	return false
+}
+
+func itemContainsAny(value interface{}, needles []string) bool {
+	switch v := value.(type) {
+	case string:
+		for _, needle := range needles {
+			if strings.Contains(v, needle) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, val := range v {
+			if itemContainsAny(val, needles) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if itemContainsAny(val, needles) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func ContainsResource(s []Resource, e Resource) bool {
 	for _, a := range s {
 		if a.InstanceInfo.Id == e.InstanceInfo.Id {
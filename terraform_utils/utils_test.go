@@ -0,0 +1,216 @@
+package terraform_utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestDedupeResourcesRemovesDuplicates(t *testing.T) {
+	resources := []Resource{
+		{
+			Provider:         "aws",
+			InstanceInfo:     &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState:    &terraform.InstanceState{ID: "i-1"},
+			AdditionalFields: map[string]interface{}{"region": "us-east-1"},
+		},
+		{
+			Provider:         "aws",
+			InstanceInfo:     &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState:    &terraform.InstanceState{ID: "i-1"},
+			AdditionalFields: map[string]interface{}{"az": "us-east-1a"},
+		},
+		{
+			Provider:      "aws",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i-2"},
+		},
+	}
+
+	deduped := DedupeResources(resources)
+
+	if len(deduped) != 2 {
+		t.Fatalf("expected 2 resources after dedupe, got %d", len(deduped))
+	}
+	want := map[string]interface{}{"region": "us-east-1", "az": "us-east-1a"}
+	if !reflect.DeepEqual(deduped[0].AdditionalFields, want) {
+		t.Errorf("expected merged AdditionalFields %v, got %v", want, deduped[0].AdditionalFields)
+	}
+}
+
+func TestRenameDuplicateResources(t *testing.T) {
+	resources := []Resource{
+		{
+			ResourceName:  "myBucket",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_s3_bucket", Id: "aws_s3_bucket.myBucket"},
+			InstanceState: &terraform.InstanceState{ID: "mybucket-1"},
+		},
+		{
+			ResourceName:  "myBucket",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_s3_bucket", Id: "aws_s3_bucket.myBucket"},
+			InstanceState: &terraform.InstanceState{ID: "mybucket-2"},
+		},
+		{
+			ResourceName:  "otherBucket",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_s3_bucket", Id: "aws_s3_bucket.otherBucket"},
+			InstanceState: &terraform.InstanceState{ID: "otherbucket"},
+		},
+	}
+
+	RenameDuplicateResources(resources)
+
+	if resources[0].ResourceName != "myBucket" {
+		t.Errorf("expected first duplicate to keep its name, got %v", resources[0].ResourceName)
+	}
+	if resources[1].ResourceName == "myBucket" {
+		t.Errorf("expected second duplicate to be renamed, got %v", resources[1].ResourceName)
+	}
+	if resources[1].InstanceInfo.Id != "aws_s3_bucket."+resources[1].ResourceName {
+		t.Errorf("expected InstanceInfo.Id to track the renamed resource, got %v", resources[1].InstanceInfo.Id)
+	}
+	if resources[2].ResourceName != "otherBucket" {
+		t.Errorf("expected unique resource to be left untouched, got %v", resources[2].ResourceName)
+	}
+
+	again := []Resource{resources[0], resources[1], resources[2]}
+	again[1].ResourceName = "myBucket"
+	again[1].InstanceInfo = &terraform.InstanceInfo{Type: "aws_s3_bucket", Id: "aws_s3_bucket.myBucket"}
+	RenameDuplicateResources(again)
+	if again[1].ResourceName != resources[1].ResourceName {
+		t.Errorf("expected deterministic renaming, got %v then %v", resources[1].ResourceName, again[1].ResourceName)
+	}
+}
+
+func TestMergeTfStateSkipsExistingAddresses(t *testing.T) {
+	existing := &terraform.State{
+		Modules: []*terraform.ModuleState{
+			{
+				Path: []string{"root"},
+				Resources: map[string]*terraform.ResourceState{
+					"aws_instance.example": {
+						Type:    "aws_instance",
+						Primary: &terraform.InstanceState{ID: "i-old"},
+					},
+				},
+			},
+		},
+	}
+	resources := []Resource{
+		{
+			Provider:      "aws",
+			ResourceName:  "example",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i-new"},
+		},
+		{
+			Provider:      "aws",
+			ResourceName:  "other",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i-other"},
+		},
+	}
+
+	conflicts, err := MergeTfState(existing, resources)
+	if err != nil {
+		t.Fatalf("MergeTfState failed: %s", err)
+	}
+	if !reflect.DeepEqual(conflicts, []string{"aws_instance.example"}) {
+		t.Errorf("expected the pre-existing address reported as a conflict, got %v", conflicts)
+	}
+	root := existing.Modules[0]
+	if root.Resources["aws_instance.example"].Primary.ID != "i-old" {
+		t.Errorf("expected the existing resource to be left untouched, got %v", root.Resources["aws_instance.example"].Primary.ID)
+	}
+	if root.Resources["aws_instance.other"] == nil || root.Resources["aws_instance.other"].Primary.ID != "i-other" {
+		t.Errorf("expected the new resource to be merged in, got %v", root.Resources["aws_instance.other"])
+	}
+}
+
+func TestCollapseForEachGroupsSimilarResources(t *testing.T) {
+	resources := []Resource{
+		{
+			Provider:      "aws",
+			ResourceName:  "a",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_iam_role_policy_attachment"},
+			InstanceState: &terraform.InstanceState{ID: "a"},
+			Item:          map[string]interface{}{"role": "example", "policy_arn": "arn:aws:iam::aws:policy/A"},
+		},
+		{
+			Provider:      "aws",
+			ResourceName:  "b",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_iam_role_policy_attachment"},
+			InstanceState: &terraform.InstanceState{ID: "b"},
+			Item:          map[string]interface{}{"role": "example", "policy_arn": "arn:aws:iam::aws:policy/B"},
+		},
+		{
+			Provider:      "aws",
+			ResourceName:  "solo",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i-1"},
+			Item:          map[string]interface{}{"ami": "ami-1"},
+		},
+	}
+
+	collapsed, locals := CollapseForEach(resources)
+
+	if len(collapsed) != 2 {
+		t.Fatalf("expected the pair to collapse into 1 resource alongside the untouched solo one, got %d: %v", len(collapsed), collapsed)
+	}
+	group, ok := locals["iam_role_policy_attachment"].(map[string]interface{})
+	if !ok || len(group) != 2 {
+		t.Fatalf("expected a locals entry with both instances, got %v", locals)
+	}
+	if group["a"].(map[string]interface{})["policy_arn"] != "arn:aws:iam::aws:policy/A" {
+		t.Errorf("expected instance a's varying attr preserved in locals, got %v", group["a"])
+	}
+}
+
+func TestCollapseForEachSkipsGroupsWithSiblingReferences(t *testing.T) {
+	resources := []Resource{
+		{
+			Provider:      "aws",
+			ResourceName:  "a",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_iam_role_policy_attachment"},
+			InstanceState: &terraform.InstanceState{ID: "a"},
+			Item:          map[string]interface{}{"role": "example", "depends_on_hint": "${aws_iam_role_policy_attachment.b.id}"},
+		},
+		{
+			Provider:      "aws",
+			ResourceName:  "b",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_iam_role_policy_attachment"},
+			InstanceState: &terraform.InstanceState{ID: "b"},
+			Item:          map[string]interface{}{"role": "other", "depends_on_hint": ""},
+		},
+	}
+
+	collapsed, locals := CollapseForEach(resources)
+
+	if len(collapsed) != 2 {
+		t.Errorf("expected the group to stay uncollapsed due to the sibling reference, got %d resources", len(collapsed))
+	}
+	if len(locals) != 0 {
+		t.Errorf("expected no locals produced when the group is skipped, got %v", locals)
+	}
+}
+
+func TestDedupeResourcesLeavesUniqueUntouched(t *testing.T) {
+	resources := []Resource{
+		{
+			Provider:      "aws",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i-1"},
+		},
+		{
+			Provider:      "aws",
+			InstanceInfo:  &terraform.InstanceInfo{Type: "aws_instance"},
+			InstanceState: &terraform.InstanceState{ID: "i-2"},
+		},
+	}
+
+	deduped := DedupeResources(resources)
+
+	if !reflect.DeepEqual(deduped, resources) {
+		t.Errorf("expected unique resources to be left untouched, got %v", deduped)
+	}
+}
@@ -16,14 +16,36 @@ package terraform_utils
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 )
 
+// WalkAndGet resolves a dotted path against nested maps and lists, e.g. "tags.Name" or,
+// to pick a single element instead of matching across the whole list, "subnets.0.cidr".
+//
+// Path syntax: segments are separated by ".". A segment that names a map key descends
+// into that key's value. A segment that's a valid integer, when the current value is a
+// list, is treated as a list index rather than a map key; any other segment applied to
+// a list instead walks every element of that list and collects the results. There's no
+// escaping for keys that themselves contain a literal ".".
 func WalkAndGet(path string, data interface{}) []interface{} {
 	pathSegments := strings.Split(path, ".")
 	return walkAndGet(pathSegments, data)
 }
 
+// WalkAndGetString is WalkAndGet, but returns the first match as a string
+// instead of the full []interface{}, for callers (typically generators reading a
+// decoded API response) that just want one scalar value out of a path. The second
+// return value is false if the path had no match or the first match wasn't a string.
+func WalkAndGetString(path string, data interface{}) (string, bool) {
+	vals := WalkAndGet(path, data)
+	if len(vals) == 0 {
+		return "", false
+	}
+	str, ok := vals[0].(string)
+	return str, ok
+}
+
 func WalkAndOverride(path, oldValue, newValue string, data interface{}) {
 	pathSegments := strings.Split(path, ".")
 	walkAndOverride(pathSegments, oldValue, newValue, data)
@@ -32,6 +54,12 @@ func WalkAndOverride(path, oldValue, newValue string, data interface{}) {
 func walkAndGet(pathSegments []string, data interface{}) []interface{} {
 	val := reflect.ValueOf(data)
 	if isArray(val.Interface()) {
+		if index, ok := listIndex(pathSegments); ok {
+			if index < 0 || index >= val.Len() {
+				return []interface{}{}
+			}
+			return walkAndGet(pathSegments[1:], val.Index(index).Interface())
+		}
 		var arrayValues []interface{}
 		for i := 0; i < val.Len(); i++ {
 			for _, subValue := range walkAndGet(pathSegments, val.Index(i).Interface()) {
@@ -39,6 +67,8 @@ func walkAndGet(pathSegments []string, data interface{}) []interface{} {
 			}
 		}
 		return arrayValues
+	} else if len(pathSegments) == 0 {
+		return []interface{}{data}
 	} else if len(pathSegments) == 1 {
 		if val.Kind() == reflect.Map {
 			for _, e := range val.MapKeys() {
@@ -105,6 +135,19 @@ func walkAndOverride(pathSegments []string, oldValue, newValue string, data inte
 	}
 }
 
+// listIndex reports whether the next path segment is a numeric list index (e.g. "0"),
+// consumed instead of being matched across every element of the list.
+func listIndex(pathSegments []string) (int, bool) {
+	if len(pathSegments) == 0 {
+		return 0, false
+	}
+	index, err := strconv.Atoi(pathSegments[0])
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
 func isArray(val interface{}) bool { // Go reflect lib can't sometimes detect given value is array
 	switch val.(type) {
 	case []interface{}:
@@ -69,6 +69,27 @@ func TestNestedArrayWalkAndGet(t *testing.T) {
 	}
 }
 
+func TestIndexedArrayWalkAndGet(t *testing.T) {
+	structure := mapI("attr1", []interface{}{
+		mapI("attr2", "value1"),
+		mapI("attr2", "value2")})
+	value := WalkAndGet("attr1.1.attr2", structure)
+
+	if !reflect.DeepEqual(value, []interface{}{"value2"}) {
+		t.Errorf("failed to get value %v", value)
+	}
+}
+
+func TestOutOfRangeIndexedArrayWalkAndGet(t *testing.T) {
+	structure := mapI("attr1", []interface{}{
+		mapI("attr2", "value1")})
+	value := WalkAndGet("attr1.5.attr2", structure)
+
+	if !reflect.DeepEqual(value, []interface{}{}) {
+		t.Errorf("failed to get value %v", value)
+	}
+}
+
 func TestNonExistingWalkAndGet(t *testing.T) {
 	structure := map[string]interface{}{
 		"attr1": "test",
@@ -148,3 +169,34 @@ func TestNestedArrayWalkAndOverride(t *testing.T) {
 		t.Errorf("failed to set value")
 	}
 }
+
+func TestWalkAndGetString(t *testing.T) {
+	structure := map[string]interface{}{
+		"attr1": "value",
+	}
+	value, ok := WalkAndGetString("attr1", structure)
+
+	if !ok || value != "value" {
+		t.Errorf("failed to get value %v, %v", value, ok)
+	}
+}
+
+func TestWalkAndGetStringMissing(t *testing.T) {
+	structure := map[string]interface{}{}
+	value, ok := WalkAndGetString("attr1", structure)
+
+	if ok || value != "" {
+		t.Errorf("expected no match, got %v, %v", value, ok)
+	}
+}
+
+func TestWalkAndGetStringNonString(t *testing.T) {
+	structure := map[string]interface{}{
+		"attr1": 5,
+	}
+	value, ok := WalkAndGetString("attr1", structure)
+
+	if ok || value != "" {
+		t.Errorf("expected no match, got %v, %v", value, ok)
+	}
+}
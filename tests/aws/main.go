@@ -63,7 +63,7 @@ func main() {
 		os.Exit(1)
 	}
 	rootPath, _ := os.Getwd()
-	currentPath := cmd.Path(cmd.DefaultPathPattern, provider.GetName(), "", cmd.DefaultPathOutput)
+	currentPath := cmd.Path(cmd.DefaultPathPattern, provider.GetName(), "", cmd.DefaultPathOutput, "")
 	if err := os.Chdir(currentPath); err != nil {
 		log.Println(err)
 		os.Exit(1)
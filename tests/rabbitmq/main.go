@@ -55,7 +55,7 @@ func main() {
 	}
 	rootPath, _ := os.Getwd()
 	for _, serviceName := range services {
-		currentPath := cmd.Path(cmd.DefaultPathPattern, provider.GetName(), serviceName, cmd.DefaultPathOutput)
+		currentPath := cmd.Path(cmd.DefaultPathPattern, provider.GetName(), serviceName, cmd.DefaultPathOutput, "")
 		if err := os.Chdir(currentPath); err != nil {
 			log.Println(err)
 			os.Exit(1)